@@ -0,0 +1,123 @@
+package ical
+
+import (
+	"fmt"
+	"time"
+)
+
+// Trigger specifies when an alarm will trigger, defined in RFC 5545 section
+// 3.8.6.3.
+//
+// A trigger is either relative to the start or the end of the parent
+// component, or absolute.
+type Trigger struct {
+	// Duration is the offset of a relative trigger.
+	Duration Duration
+	// Related is the part of the parent component a relative trigger is
+	// related to. An empty value is equivalent to RelatedStart.
+	Related Related
+	// Absolute is the time of an absolute trigger. If it's the zero time, the
+	// trigger is relative.
+	Absolute time.Time
+}
+
+// IsAbsolute checks whether the trigger is absolute.
+func (trigger *Trigger) IsAbsolute() bool {
+	return !trigger.Absolute.IsZero()
+}
+
+// Resolve returns the time at which the trigger fires, for an alarm nested
+// in event. loc is used for floating date-times in event.
+//
+// TZIDs are resolved with DefaultTimezoneResolver, use ResolveWith for events
+// whose TZID is defined by a VTIMEZONE of their calendar.
+func (trigger *Trigger) Resolve(event *Event, loc *time.Location) (time.Time, error) {
+	return trigger.ResolveWith(event, loc, nil)
+}
+
+// ResolveWith is like Resolve, but resolves TZIDs with resolver, e.g. the
+// Calendar containing event. If resolver is nil, DefaultTimezoneResolver is
+// used.
+func (trigger *Trigger) ResolveWith(event *Event, loc *time.Location, resolver TimezoneResolver) (time.Time, error) {
+	if trigger.IsAbsolute() {
+		return trigger.Absolute, nil
+	}
+
+	var (
+		t   time.Time
+		err error
+	)
+	switch trigger.Related {
+	case "", RelatedStart:
+		t, err = event.DateTimeStartWith(loc, resolver)
+	case RelatedEnd:
+		t, err = event.DateTimeEndWith(loc, resolver)
+	default:
+		return time.Time{}, fmt.Errorf("ical: invalid trigger relationship: %q", trigger.Related)
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	if t.IsZero() {
+		return time.Time{}, fmt.Errorf("ical: cannot resolve trigger: event has no start time")
+	}
+
+	return trigger.Duration.AddTo(t), nil
+}
+
+// Trigger parses the property value as an alarm trigger.
+func (prop *Prop) Trigger() (*Trigger, error) {
+	if prop.ValueType() == ValueDateTime {
+		t, err := prop.DateTime(time.UTC)
+		if err != nil {
+			return nil, err
+		}
+		return &Trigger{Absolute: t.UTC()}, nil
+	}
+
+	dur, err := prop.Duration()
+	if err != nil {
+		return nil, err
+	}
+	related, err := prop.Params.Related()
+	if err != nil {
+		return nil, err
+	}
+	return &Trigger{Duration: dur, Related: related}, nil
+}
+
+// SetTrigger sets the property value to a trigger. An error is returned if
+// the duration of a relative trigger has a sub-second part.
+func (prop *Prop) SetTrigger(trigger *Trigger) error {
+	if trigger.IsAbsolute() {
+		prop.Params.Del(ParamRelated)
+		prop.SetDateTime(trigger.Absolute.UTC())
+		return nil
+	}
+
+	if err := prop.SetDuration(trigger.Duration); err != nil {
+		return err
+	}
+	prop.Params.Del(ParamRelated)
+	if trigger.Related == RelatedEnd {
+		prop.Params.Set(ParamRelated, string(RelatedEnd))
+	}
+	return nil
+}
+
+// Trigger returns the TRIGGER property, or nil if it's absent.
+func (props Props) Trigger() (*Trigger, error) {
+	if prop := props.Get(PropTrigger); prop != nil {
+		return prop.Trigger()
+	}
+	return nil, nil
+}
+
+func (props Props) SetTrigger(trigger *Trigger) error {
+	prop := NewProp(PropTrigger)
+	if err := prop.SetTrigger(trigger); err != nil {
+		return err
+	}
+	props.Set(prop)
+	return nil
+}
@@ -0,0 +1,141 @@
+package ical
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTrigger(t *testing.T) {
+	testCases := []struct {
+		Alias    string
+		Prop     Prop
+		Expected *Trigger
+	}{
+		{
+			Alias:    "relative-start",
+			Prop:     Prop{Name: PropTrigger, Params: Params{}, Value: "-PT15M"},
+			Expected: &Trigger{Duration: Duration{Time: -15 * time.Minute}},
+		},
+		{
+			Alias: "relative-end",
+			Prop: Prop{
+				Name:   PropTrigger,
+				Params: Params{ParamRelated: []string{"END"}},
+				Value:  "PT5M",
+			},
+			Expected: &Trigger{Duration: Duration{Time: 5 * time.Minute}, Related: RelatedEnd},
+		},
+		{
+			Alias: "absolute",
+			Prop: Prop{
+				Name:   PropTrigger,
+				Params: Params{ParamValue: []string{"DATE-TIME"}},
+				Value:  "19980101T050000Z",
+			},
+			Expected: &Trigger{Absolute: time.Date(1998, time.January, 1, 5, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	for _, tCase := range testCases {
+		t.Run(tCase.Alias, func(t *testing.T) {
+			trigger, err := tCase.Prop.Trigger()
+			if err != nil {
+				t.Fatalf("Prop.Trigger() = %v", err)
+			}
+			if !reflect.DeepEqual(trigger, tCase.Expected) {
+				t.Errorf("Prop.Trigger() = %#v, want %#v", trigger, tCase.Expected)
+			}
+
+			prop := NewProp(PropTrigger)
+			if err := prop.SetTrigger(trigger); err != nil {
+				t.Fatalf("Prop.SetTrigger() = %v", err)
+			}
+			if !reflect.DeepEqual(prop, &tCase.Prop) {
+				t.Errorf("Prop.SetTrigger() = %#v, want %#v", prop, &tCase.Prop)
+			}
+		})
+	}
+}
+
+func TestTriggerResolve(t *testing.T) {
+	event := NewEvent()
+	event.Props.SetDateTime(PropDateTimeStart, time.Date(2024, time.May, 1, 10, 0, 0, 0, time.UTC))
+	event.Props.SetDateTime(PropDateTimeEnd, time.Date(2024, time.May, 1, 11, 0, 0, 0, time.UTC))
+
+	testCases := []struct {
+		Alias    string
+		Trigger  *Trigger
+		Expected time.Time
+	}{
+		{
+			Alias:    "start",
+			Trigger:  &Trigger{Duration: Duration{Time: -15 * time.Minute}},
+			Expected: time.Date(2024, time.May, 1, 9, 45, 0, 0, time.UTC),
+		},
+		{
+			Alias:    "end",
+			Trigger:  &Trigger{Duration: Duration{Days: -1}, Related: RelatedEnd},
+			Expected: time.Date(2024, time.April, 30, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			Alias:    "absolute",
+			Trigger:  &Trigger{Absolute: time.Date(2024, time.April, 1, 8, 0, 0, 0, time.UTC)},
+			Expected: time.Date(2024, time.April, 1, 8, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tCase := range testCases {
+		t.Run(tCase.Alias, func(t *testing.T) {
+			got, err := tCase.Trigger.Resolve(event, nil)
+			if err != nil {
+				t.Fatalf("Trigger.Resolve() = %v", err)
+			}
+			if !got.Equal(tCase.Expected) {
+				t.Errorf("Trigger.Resolve() = %v, want %v", got, tCase.Expected)
+			}
+		})
+	}
+}
+
+func TestTriggerResolveWith(t *testing.T) {
+	cal, err := NewDecoder(strings.NewReader(customTimezoneCalendarStr)).Decode()
+	if err != nil {
+		t.Fatalf("Decode() = %v", err)
+	}
+	event := &cal.Events()[0]
+
+	testCases := []struct {
+		Alias    string
+		Trigger  *Trigger
+		Expected time.Time
+	}{
+		{
+			Alias:    "start",
+			Trigger:  &Trigger{Duration: Duration{Time: -15 * time.Minute}},
+			Expected: time.Date(2024, time.July, 15, 7, 45, 0, 0, time.UTC),
+		},
+		{
+			Alias:    "end",
+			Trigger:  &Trigger{Duration: Duration{Time: 5 * time.Minute}, Related: RelatedEnd},
+			Expected: time.Date(2024, time.July, 15, 9, 5, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tCase := range testCases {
+		t.Run(tCase.Alias, func(t *testing.T) {
+			got, err := tCase.Trigger.ResolveWith(event, nil, cal)
+			if err != nil {
+				t.Fatalf("Trigger.ResolveWith() = %v", err)
+			}
+			if !got.Equal(tCase.Expected) {
+				t.Errorf("Trigger.ResolveWith() = %v, want %v", got, tCase.Expected)
+			}
+
+			if _, err := tCase.Trigger.Resolve(event, nil); err == nil {
+				t.Errorf("Trigger.Resolve() = nil, want an error without the calendar")
+			}
+		})
+	}
+}
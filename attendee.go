@@ -0,0 +1,229 @@
+package ical
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Attendee is a participant in a calendar component, defined in RFC 5545
+// section 3.8.4.1.
+type Attendee struct {
+	// Address is the calendar user address, e.g. a "mailto:" URI.
+	Address *url.URL
+
+	CommonName          string
+	CalendarUserType    CalendarUserType
+	Role                Role
+	ParticipationStatus ParticipationStatus
+	RSVP                bool
+	DelegatedTo         []*url.URL
+	DelegatedFrom       []*url.URL
+	SentBy              *url.URL
+	Member              []*url.URL
+	Dir                 *url.URL
+	// Email is the email address of the attendee, if it differs from the
+	// calendar user address. Defined in RFC 7986 section 6.2.
+	Email string
+}
+
+// EmailAddress returns the email address of the attendee: the EMAIL
+// parameter if set, otherwise the address of a "mailto:" calendar user
+// address.
+func (attendee *Attendee) EmailAddress() string {
+	return emailAddress(attendee.Address, attendee.Email)
+}
+
+// Organizer is the organizer of a calendar component, defined in RFC 5545
+// section 3.8.4.3.
+type Organizer struct {
+	// Address is the calendar user address, e.g. a "mailto:" URI.
+	Address *url.URL
+
+	CommonName string
+	SentBy     *url.URL
+	Dir        *url.URL
+	// Email is the email address of the organizer, if it differs from the
+	// calendar user address. Defined in RFC 7986 section 6.2.
+	Email string
+}
+
+// EmailAddress returns the email address of the organizer: the EMAIL
+// parameter if set, otherwise the address of a "mailto:" calendar user
+// address.
+func (organizer *Organizer) EmailAddress() string {
+	return emailAddress(organizer.Address, organizer.Email)
+}
+
+func emailAddress(u *url.URL, email string) string {
+	if email != "" {
+		return email
+	}
+	if u != nil && strings.EqualFold(u.Scheme, "mailto") {
+		return u.Opaque
+	}
+	return ""
+}
+
+func parseAddressParam(params Params, name string) (*url.URL, error) {
+	v := params.Get(name)
+	if v == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(v)
+	if err != nil {
+		return nil, fmt.Errorf("ical: invalid %v parameter: %v", name, err)
+	}
+	return u, nil
+}
+
+func parseAddressListParam(params Params, name string) ([]*url.URL, error) {
+	var l []*url.URL
+	for _, v := range params.Values(name) {
+		u, err := url.Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("ical: invalid %v parameter: %v", name, err)
+		}
+		l = append(l, u)
+	}
+	return l, nil
+}
+
+func setAddressParam(params Params, name string, u *url.URL) {
+	if u != nil {
+		params.Set(name, u.String())
+	} else {
+		params.Del(name)
+	}
+}
+
+func setAddressListParam(params Params, name string, l []*url.URL) {
+	params.Del(name)
+	for _, u := range l {
+		params.Add(name, u.String())
+	}
+}
+
+// Attendee parses the property as an attendee.
+func (prop *Prop) Attendee() (*Attendee, error) {
+	return prop.attendee("", false)
+}
+
+// attendee parses the property as an attendee of a component. In lenient
+// mode, invalid enumerated parameters are kept as-is, an RSVP parameter other
+// than TRUE is false and invalid address parameters are ignored, so that
+// errors are only returned for an invalid calendar user address.
+func (prop *Prop) attendee(compName string, lenient bool) (*Attendee, error) {
+	addr, err := prop.CalendarAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	attendee := &Attendee{
+		Address:    addr,
+		CommonName: prop.Params.Get(ParamCommonName),
+		Email:      prop.Params.Get(ParamEmail),
+	}
+
+	raw := func(name string) string {
+		return strings.ToUpper(prop.Params.Get(name))
+	}
+	if attendee.CalendarUserType, err = prop.Params.CalendarUserType(); err != nil && lenient {
+		attendee.CalendarUserType, err = CalendarUserType(raw(ParamCalendarUserType)), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if attendee.Role, err = prop.Params.Role(); err != nil && lenient {
+		attendee.Role, err = Role(raw(ParamRole)), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if attendee.ParticipationStatus, err = prop.Params.ParticipationStatus(compName); err != nil && lenient {
+		attendee.ParticipationStatus, err = ParticipationStatus(raw(ParamParticipationStatus)), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if attendee.RSVP, err = prop.Params.RSVP(); err != nil && lenient {
+		attendee.RSVP, err = false, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Address parameters are nil if they are invalid
+	if attendee.DelegatedTo, err = parseAddressListParam(prop.Params, ParamDelegatedTo); err != nil && !lenient {
+		return nil, err
+	}
+	if attendee.DelegatedFrom, err = parseAddressListParam(prop.Params, ParamDelegatedFrom); err != nil && !lenient {
+		return nil, err
+	}
+	if attendee.Member, err = parseAddressListParam(prop.Params, ParamMember); err != nil && !lenient {
+		return nil, err
+	}
+	if attendee.SentBy, err = parseAddressParam(prop.Params, ParamSentBy); err != nil && !lenient {
+		return nil, err
+	}
+	if attendee.Dir, err = parseAddressParam(prop.Params, ParamDir); err != nil && !lenient {
+		return nil, err
+	}
+
+	return attendee, nil
+}
+
+// SetAttendee sets the property value and parameters from an attendee.
+// Parameters unknown to Attendee are left untouched.
+func (prop *Prop) SetAttendee(attendee *Attendee) {
+	prop.SetCalendarAddress(attendee.Address)
+
+	setParam(prop.Params, ParamCommonName, attendee.CommonName)
+	setParam(prop.Params, ParamCalendarUserType, string(attendee.CalendarUserType))
+	setParam(prop.Params, ParamRole, string(attendee.Role))
+	setParam(prop.Params, ParamParticipationStatus, string(attendee.ParticipationStatus))
+	setParam(prop.Params, ParamEmail, attendee.Email)
+	if attendee.RSVP {
+		prop.Params.Set(ParamRSVP, "TRUE")
+	} else {
+		prop.Params.Del(ParamRSVP)
+	}
+	setAddressListParam(prop.Params, ParamDelegatedTo, attendee.DelegatedTo)
+	setAddressListParam(prop.Params, ParamDelegatedFrom, attendee.DelegatedFrom)
+	setAddressListParam(prop.Params, ParamMember, attendee.Member)
+	setAddressParam(prop.Params, ParamSentBy, attendee.SentBy)
+	setAddressParam(prop.Params, ParamDir, attendee.Dir)
+}
+
+// Organizer parses the property as an organizer.
+func (prop *Prop) Organizer() (*Organizer, error) {
+	addr, err := prop.CalendarAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	organizer := &Organizer{
+		Address:    addr,
+		CommonName: prop.Params.Get(ParamCommonName),
+		Email:      prop.Params.Get(ParamEmail),
+	}
+	if organizer.SentBy, err = parseAddressParam(prop.Params, ParamSentBy); err != nil {
+		return nil, err
+	}
+	if organizer.Dir, err = parseAddressParam(prop.Params, ParamDir); err != nil {
+		return nil, err
+	}
+
+	return organizer, nil
+}
+
+// SetOrganizer sets the property value and parameters from an organizer.
+// Parameters unknown to Organizer are left untouched.
+func (prop *Prop) SetOrganizer(organizer *Organizer) {
+	prop.SetCalendarAddress(organizer.Address)
+
+	setParam(prop.Params, ParamCommonName, organizer.CommonName)
+	setParam(prop.Params, ParamEmail, organizer.Email)
+	setAddressParam(prop.Params, ParamSentBy, organizer.SentBy)
+	setAddressParam(prop.Params, ParamDir, organizer.Dir)
+}
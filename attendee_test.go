@@ -0,0 +1,125 @@
+package ical
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func mustParseURL(s string) *url.URL {
+	u, err := url.Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+func TestAttendee(t *testing.T) {
+	prop := Prop{
+		Name: PropAttendee,
+		Params: Params{
+			ParamCommonName:          []string{"Jane Doe"},
+			ParamCalendarUserType:    []string{"individual"},
+			ParamRole:                []string{"REQ-PARTICIPANT"},
+			ParamParticipationStatus: []string{"DELEGATED"},
+			ParamRSVP:                []string{"TRUE"},
+			ParamDelegatedTo:         []string{"mailto:jdoe@example.com", "mailto:jqpublic@example.com"},
+			ParamSentBy:              []string{"mailto:sray@example.com"},
+			ParamMember:              []string{"mailto:ietf-calsch@example.org"},
+			ParamDir:                 []string{"ldap://example.com:6666/o=ABC%20Industries,c=US???(cn=Jane%20Doe)"},
+			ParamEmail:               []string{"jane@example.org"},
+			"X-FOO":                  []string{"bar"},
+		},
+		Value: "mailto:jane_doe@example.com",
+	}
+
+	want := &Attendee{
+		Address:             mustParseURL("mailto:jane_doe@example.com"),
+		CommonName:          "Jane Doe",
+		CalendarUserType:    CalendarUserIndividual,
+		Role:                RoleReqParticipant,
+		ParticipationStatus: ParticipationDelegated,
+		RSVP:                true,
+		DelegatedTo: []*url.URL{
+			mustParseURL("mailto:jdoe@example.com"),
+			mustParseURL("mailto:jqpublic@example.com"),
+		},
+		SentBy: mustParseURL("mailto:sray@example.com"),
+		Member: []*url.URL{mustParseURL("mailto:ietf-calsch@example.org")},
+		Dir:    mustParseURL("ldap://example.com:6666/o=ABC%20Industries,c=US???(cn=Jane%20Doe)"),
+		Email:  "jane@example.org",
+	}
+
+	attendee, err := prop.Attendee()
+	if err != nil {
+		t.Fatalf("Prop.Attendee() = %v", err)
+	}
+	if !reflect.DeepEqual(attendee, want) {
+		t.Errorf("Prop.Attendee() = %#v, want %#v", attendee, want)
+	}
+	if got, want := attendee.EmailAddress(), "jane@example.org"; got != want {
+		t.Errorf("Attendee.EmailAddress() = %v, want %v", got, want)
+	}
+
+	event := NewEvent()
+	event.SetAttendees([]Attendee{*attendee})
+	l, err := event.Attendees()
+	if err != nil {
+		t.Fatalf("Event.Attendees() = %v", err)
+	}
+	if len(l) != 1 || !reflect.DeepEqual(&l[0], want) {
+		t.Errorf("Event.Attendees() = %#v, want %#v", l, []Attendee{*want})
+	}
+}
+
+func TestOrganizer(t *testing.T) {
+	event := exampleCalendar.Events()[0]
+
+	want := &Organizer{Address: mustParseURL("mailto:jsmith@example.com")}
+	organizer, err := event.Organizer()
+	if err != nil {
+		t.Fatalf("Event.Organizer() = %v", err)
+	}
+	if !reflect.DeepEqual(organizer, want) {
+		t.Errorf("Event.Organizer() = %#v, want %#v", organizer, want)
+	}
+	if got, want := organizer.EmailAddress(), "jsmith@example.com"; got != want {
+		t.Errorf("Organizer.EmailAddress() = %v, want %v", got, want)
+	}
+
+	if organizer, err := NewEvent().Organizer(); organizer != nil || err != nil {
+		t.Errorf("Event.Organizer() = %v, %v, want nil, nil", organizer, err)
+	}
+}
+
+func TestEventAttendeesLenient(t *testing.T) {
+	event := NewEvent()
+	event.Props.Add(&Prop{
+		Name:   PropAttendee,
+		Params: Params{ParamParticipationStatus: []string{"COMPLETED"}, ParamRSVP: []string{"yes"}},
+		Value:  "mailto:jane@example.org",
+	})
+	event.Props.Add(&Prop{
+		Name:   PropAttendee,
+		Params: Params{ParamRole: []string{"CHAIR"}, ParamSentBy: []string{"%zz"}},
+		Value:  "mailto:john@example.org",
+	})
+
+	if _, err := event.Props[PropAttendee][0].Attendee(); err == nil {
+		t.Errorf("Prop.Attendee() = nil, want an error")
+	}
+
+	l, err := event.Attendees()
+	if err != nil {
+		t.Fatalf("Event.Attendees() = %v", err)
+	}
+	if len(l) != 2 {
+		t.Fatalf("Event.Attendees() = %v attendees, want 2", len(l))
+	}
+	if l[0].ParticipationStatus != "COMPLETED" || l[0].RSVP {
+		t.Errorf("Event.Attendees()[0] = %#v, want PARTSTAT=COMPLETED without RSVP", l[0])
+	}
+	if l[1].Role != RoleChair || l[1].SentBy != nil {
+		t.Errorf("Event.Attendees()[1] = %#v, want ROLE=CHAIR without SENT-BY", l[1])
+	}
+}
@@ -0,0 +1,343 @@
+package ical
+
+import (
+	"math"
+	"sync"
+)
+
+// Chinese calendar arithmetic, following "Calendrical Calculations" by
+// Reingold and Dershowitz. The solar longitude and new moons are computed
+// with the algorithms from "Astronomical Algorithms" by Meeus, which are
+// accurate to a few minutes over the years supported by this package.
+//
+// Moments are fractional fixed day numbers, in universal time unless stated
+// otherwise. Years are counted from the epoch of the calendar, 2637 BCE.
+
+const (
+	meanTropicalYear = 365.242189
+	meanSynodicMonth = 29.530588861
+	// Fixed day number of the first day of the Chinese calendar.
+	chineseEpoch = -963099
+	// Julian day number of the fixed moment 0.
+	fixedJulianDay = 1721424.5
+	// Moment of the first new moon of 2000, in dynamical time.
+	newMoonEpoch = 2451550.09766 - fixedJulianDay
+)
+
+func sinDeg(deg float64) float64 {
+	return math.Sin(deg * math.Pi / 180)
+}
+
+func modFloat(x, y float64) float64 {
+	return x - y*math.Floor(x/y)
+}
+
+// amod is like mod, but returns a value in [1, y] instead of [0, y[.
+func amod(x, y int) int {
+	return mod(x-1, y) + 1
+}
+
+// ephemerisCorrection returns the difference between dynamical time and
+// universal time at a moment, in days.
+func ephemerisCorrection(t float64) float64 {
+	year := 2000 + (t-730120.5)/365.2425
+	var seconds float64
+	if year >= 2005 && year < 2050 {
+		u := year - 2000
+		seconds = 62.92 + 0.32217*u + 0.005589*u*u
+	} else {
+		u := (year - 1820) / 100
+		seconds = -20 + 32*u*u
+	}
+	return seconds / 86400
+}
+
+// solarLongitude returns the apparent longitude of the sun at a moment, in
+// degrees.
+func solarLongitude(t float64) float64 {
+	c := (t + ephemerisCorrection(t) + fixedJulianDay - 2451545) / 36525
+	l := 280.46646 + 36000.76983*c + 0.0003032*c*c
+	m := 357.52911 + 35999.05029*c - 0.0001537*c*c
+	eq := (1.914602-0.004817*c-0.000014*c*c)*sinDeg(m) +
+		(0.019993-0.000101*c)*sinDeg(2*m) +
+		0.000289*sinDeg(3*m)
+	omega := 125.04 - 1934.136*c
+	return modFloat(l+eq-0.00569-0.00478*sinDeg(omega), 360)
+}
+
+// estimatePriorSolarLongitude returns an estimate of the last moment before
+// t at which the solar longitude was lambda.
+func estimatePriorSolarLongitude(lambda, t float64) float64 {
+	rate := meanTropicalYear / 360
+	tau := t - rate*modFloat(solarLongitude(t)-lambda, 360)
+	delta := modFloat(solarLongitude(tau)-lambda+180, 360) - 180
+	return math.Min(t, tau-rate*delta)
+}
+
+// Periodic terms of the new moon correction: coefficient, power of the
+// eccentricity, and multiples of the sun's mean anomaly, the moon's mean
+// anomaly and the moon's argument of latitude.
+var newMoonTerms = []struct {
+	coeff             float64
+	e, sun, moon, lat float64
+}{
+	{-0.40720, 0, 0, 1, 0},
+	{0.17241, 1, 1, 0, 0},
+	{0.01608, 0, 0, 2, 0},
+	{0.01039, 0, 0, 0, 2},
+	{0.00739, 1, -1, 1, 0},
+	{-0.00514, 1, 1, 1, 0},
+	{0.00208, 2, 2, 0, 0},
+	{-0.00111, 0, 0, 1, -2},
+	{-0.00057, 0, 0, 1, 2},
+	{0.00056, 1, 1, 2, 0},
+	{-0.00042, 0, 0, 3, 0},
+	{0.00042, 1, 1, 0, 2},
+	{0.00038, 1, 1, 0, -2},
+	{-0.00024, 1, -1, 2, 0},
+	{-0.00007, 0, 2, 1, 0},
+	{0.00004, 0, 0, 2, -2},
+	{0.00004, 0, 3, 0, 0},
+	{0.00003, 0, 1, 1, -2},
+	{0.00003, 0, 0, 2, 2},
+	{-0.00003, 0, 1, 1, 2},
+	{0.00003, 0, -1, 1, 2},
+	{-0.00002, 0, -1, 1, -2},
+	{-0.00002, 0, 1, 3, 0},
+	{0.00002, 0, 0, 4, 0},
+}
+
+// Planetary arguments of the new moon correction: constant and rate in
+// degrees, and coefficient.
+var newMoonPlanetaryTerms = []struct {
+	base, rate, coeff float64
+}{
+	{251.88, 0.016321, 0.000165},
+	{251.83, 26.651886, 0.000164},
+	{349.42, 36.412478, 0.000126},
+	{84.66, 18.206239, 0.000110},
+	{141.74, 53.303771, 0.000062},
+	{207.14, 2.453732, 0.000060},
+	{154.84, 7.306860, 0.000056},
+	{34.52, 27.261239, 0.000047},
+	{207.19, 0.121824, 0.000042},
+	{291.34, 1.844379, 0.000040},
+	{161.72, 24.198154, 0.000037},
+	{239.56, 25.513099, 0.000035},
+	{331.55, 3.592518, 0.000023},
+}
+
+// nthNewMoon returns the moment of the n-th new moon after the first one of
+// 2000.
+func nthNewMoon(n int) float64 {
+	k := float64(n)
+	c := k / 1236.85
+	t := newMoonEpoch + meanSynodicMonth*k + 0.00015437*c*c - 0.000000150*c*c*c + 0.00000000073*c*c*c*c
+	e := 1 - 0.002516*c - 0.0000074*c*c
+	sun := 2.5534 + 29.10535670*k - 0.0000014*c*c - 0.00000011*c*c*c
+	moon := 201.5643 + 385.81693528*k + 0.0107582*c*c + 0.00001238*c*c*c - 0.000000058*c*c*c*c
+	lat := 160.7108 + 390.67050284*k - 0.0016118*c*c - 0.00000227*c*c*c + 0.000000011*c*c*c*c
+	omega := 124.7746 - 1.56375588*k + 0.0020672*c*c + 0.00000215*c*c*c
+
+	for _, term := range newMoonTerms {
+		t += term.coeff * math.Pow(e, term.e) * sinDeg(term.sun*sun+term.moon*moon+term.lat*lat)
+	}
+	t -= 0.00017 * sinDeg(omega)
+	t += 0.000325 * sinDeg(299.77+0.107408*k-0.009173*c*c)
+	for _, term := range newMoonPlanetaryTerms {
+		t += term.coeff * sinDeg(term.base+term.rate*k)
+	}
+
+	return t - ephemerisCorrection(t)
+}
+
+func newMoonAtOrAfter(t float64) float64 {
+	n := int(math.Floor((t-newMoonEpoch)/meanSynodicMonth)) - 1
+	for nthNewMoon(n) < t {
+		n++
+	}
+	return nthNewMoon(n)
+}
+
+func newMoonBefore(t float64) float64 {
+	n := int(math.Floor((t-newMoonEpoch)/meanSynodicMonth)) + 1
+	for nthNewMoon(n) >= t {
+		n--
+	}
+	return nthNewMoon(n)
+}
+
+// chinaOffset returns the offset of the Chinese standard time, in days.
+func chinaOffset(t float64) float64 {
+	if year, _, _ := dateFromFixed(int(math.Floor(t))); year < 1929 {
+		// Beijing mean solar time
+		return 1397.0 / 180 / 24
+	}
+	return 8.0 / 24
+}
+
+func midnightInChina(date int) float64 {
+	return float64(date) - chinaOffset(float64(date))
+}
+
+func chineseDate(t float64) int {
+	return int(math.Floor(t + chinaOffset(t)))
+}
+
+func chineseWinterSolsticeOnOrBefore(date int) int {
+	approx := estimatePriorSolarLongitude(270, midnightInChina(date+1))
+	day := int(math.Floor(approx)) - 1
+	for solarLongitude(midnightInChina(day+1)) <= 270 {
+		day++
+	}
+	return day
+}
+
+func chineseNewMoonOnOrAfter(date int) int {
+	return chineseDate(newMoonAtOrAfter(midnightInChina(date)))
+}
+
+func chineseNewMoonBefore(date int) int {
+	return chineseDate(newMoonBefore(midnightInChina(date)))
+}
+
+func chineseMajorSolarTerm(date int) int {
+	s := solarLongitude(midnightInChina(date))
+	return amod(2+int(math.Floor(s/30)), 12)
+}
+
+func chineseNoMajorSolarTerm(date int) bool {
+	return chineseMajorSolarTerm(date) == chineseMajorSolarTerm(chineseNewMoonOnOrAfter(date+1))
+}
+
+// chinesePriorLeapMonth reports whether there is a leap month between the
+// months starting at start and end, inclusive.
+func chinesePriorLeapMonth(start, end int) bool {
+	for end >= start {
+		if chineseNoMajorSolarTerm(end) {
+			return true
+		}
+		end = chineseNewMoonBefore(end)
+	}
+	return false
+}
+
+func monthsBetween(start, end int) int {
+	return int(math.Round(float64(end-start) / meanSynodicMonth))
+}
+
+func chineseFromFixed(fixed int) calendarDate {
+	s1 := chineseWinterSolsticeOnOrBefore(fixed)
+	s2 := chineseWinterSolsticeOnOrBefore(s1 + 370)
+	m12 := chineseNewMoonOnOrAfter(s1 + 1)
+	nextM11 := chineseNewMoonBefore(s2 + 1)
+	m := chineseNewMoonBefore(fixed + 1)
+	leapYear := monthsBetween(m12, nextM11) == 12
+
+	month := monthsBetween(m12, m)
+	if leapYear && chinesePriorLeapMonth(m12, m) {
+		month--
+	}
+	month = amod(month, 12)
+	leap := leapYear && chineseNoMajorSolarTerm(m) && !chinesePriorLeapMonth(m12, chineseNewMoonBefore(m))
+	year := int(math.Floor(1.5 - float64(month)/12 + float64(fixed-chineseEpoch)/meanTropicalYear))
+
+	return calendarDate{
+		Year:  year,
+		Month: RecurMonth{Month: month, Leap: leap},
+		Day:   fixed - m + 1,
+	}
+}
+
+// chineseNewYearInSui returns the fixed day number of the new year of the
+// solar year (sui) containing date.
+func chineseNewYearInSui(date int) int {
+	s1 := chineseWinterSolsticeOnOrBefore(date)
+	s2 := chineseWinterSolsticeOnOrBefore(s1 + 370)
+	m12 := chineseNewMoonOnOrAfter(s1 + 1)
+	m13 := chineseNewMoonOnOrAfter(m12 + 1)
+	nextM11 := chineseNewMoonBefore(s2 + 1)
+	if monthsBetween(m12, nextM11) == 12 && (chineseNoMajorSolarTerm(m12) || chineseNoMajorSolarTerm(m13)) {
+		return chineseNewMoonOnOrAfter(m13 + 1)
+	}
+	return m13
+}
+
+func chineseNewYearOnOrBefore(date int) int {
+	if newYear := chineseNewYearInSui(date); date >= newYear {
+		return newYear
+	}
+	return chineseNewYearInSui(date - 180)
+}
+
+type chineseMonth struct {
+	month RecurMonth
+	start int
+}
+
+// chineseYear holds the months of a year, followed by the first month of
+// the next year.
+type chineseYear []chineseMonth
+
+func (months chineseYear) find(month RecurMonth) int {
+	for i, m := range months[:len(months)-1] {
+		if m.month == month {
+			return i
+		}
+	}
+	return -1
+}
+
+// chineseCalendar caches the months of the years it has computed, since
+// these computations are expensive.
+type chineseCalendar struct {
+	mutex sync.Mutex
+	years map[int]chineseYear
+}
+
+func newChineseCalendar() *chineseCalendar {
+	return &chineseCalendar{years: make(map[int]chineseYear)}
+}
+
+func (cal *chineseCalendar) year(year int) chineseYear {
+	cal.mutex.Lock()
+	defer cal.mutex.Unlock()
+
+	if months, ok := cal.years[year]; ok {
+		return months
+	}
+
+	midYear := int(math.Floor(chineseEpoch + (float64(year)-0.5)*meanTropicalYear))
+	start := chineseNewYearOnOrBefore(midYear)
+	var months chineseYear
+	for {
+		date := chineseFromFixed(start)
+		months = append(months, chineseMonth{month: date.Month, start: start})
+		if date.Year != year {
+			break
+		}
+		start = chineseNewMoonOnOrAfter(start + 1)
+	}
+
+	cal.years[year] = months
+	return months
+}
+
+func (cal *chineseCalendar) fromFixed(fixed int) calendarDate {
+	return chineseFromFixed(fixed)
+}
+
+func (cal *chineseCalendar) monthStart(year int, month RecurMonth) int {
+	months := cal.year(year)
+	return months[months.find(month)].start
+}
+
+func (cal *chineseCalendar) monthLength(year int, month RecurMonth) int {
+	months := cal.year(year)
+	i := months.find(month)
+	return months[i+1].start - months[i].start
+}
+
+func (cal *chineseCalendar) hasLeapMonth(year, month int) bool {
+	return cal.year(year).find(RecurMonth{Month: month, Leap: true}) >= 0
+}
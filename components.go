@@ -13,45 +13,111 @@ type Calendar struct {
 	*Component
 }
 
-// RecurrenceSet returns the Recurrence Set for this component.
+// RecurrenceSet returns the Recurrence Set for this component. TZIDs are
+// resolved with DefaultTimezoneResolver, use RecurrenceSetWith to resolve the
+// TZIDs defined by a VTIMEZONE of the calendar.
+//
+// Rules evaluated in a non-Gregorian calendar system (RSCALE, defined in RFC
+// 7529) are expanded up to 100 years after DTSTART, and their occurrences are
+// added to the set as recurrence dates.
+//
+// The rule of the set resolves local times in a DST gap or overlap like
+// time.Date. For rules with a COUNT or an UNTIL, the affected occurrences are
+// replaced with the ones defined by RFC 5545 section 3.3.5.
+//
+// Rules without a COUNT or an UNTIL aren't adjusted, apart from DTSTART,
+// since a set can't be computed lazily: their occurrences don't follow RFC
+// 5545 section 3.3.5. For instance, a daily occurrence at 02:30 on the day
+// clocks move forward from 02:00 to 03:00 may be moved backward to 01:30
+// instead of forward to 03:30, depending on the zone. Use
+// Component.RecurrenceIterator or Calendar.Occurrences to get the occurrences
+// of such rules as specified by the RFC.
 func (comp *Component) RecurrenceSet(loc *time.Location) (*rrule.Set, error) {
-	roption, err := comp.Props.RecurrenceRule()
+	return comp.recurrenceSet(loc, nil)
+}
+
+// RecurrenceSetWith is like RecurrenceSet, but resolves TZIDs with resolver,
+// e.g. the Calendar containing the component. A nil resolver uses
+// DefaultTimezoneResolver.
+func (comp *Component) RecurrenceSetWith(loc *time.Location, resolver TimezoneResolver) (*rrule.Set, error) {
+	return comp.recurrenceSet(loc, resolverLoader(resolver))
+}
+
+func (comp *Component) recurrenceSet(loc *time.Location, load timezoneLoader) (*rrule.Set, error) {
+	recur, err := comp.Props.Recur()
 	if err != nil {
 		return nil, fmt.Errorf("ical: error parsing recurrence: %v", err)
 	}
-	if roption == nil {
+	if recur == nil {
 		return nil, nil
 	}
-	dateTime, err := comp.Props.DateTime(PropDateTimeStart, loc)
-	if err != nil {
-		return nil, fmt.Errorf("ical: error parsing start time: %v", err)
+	var dtstart DateTime
+	var dateTime time.Time
+	if prop := comp.Props.Get(PropDateTimeStart); prop != nil {
+		dtstart, err = prop.DateTimeValue()
+		if err == nil {
+			dateTime, err = dtstart.time(loc, load)
+		}
 	}
-
-	rule, err := rrule.NewRRule(*roption)
 	if err != nil {
-		return nil, fmt.Errorf("ical: error buildling rrule: %v", err)
+		return nil, fmt.Errorf("ical: error parsing start time: %v", err)
 	}
 
 	ruleSet := rrule.Set{}
-	ruleSet.RRule(rule)
+	if recur.needsRScale() {
+		it, err := recur.rscaleIterator(dateTime)
+		if err != nil {
+			return nil, fmt.Errorf("ical: error parsing recurrence: %v", err)
+		}
+		horizon := dateTime.AddDate(rscaleHorizonYears, 0, 0)
+		for {
+			t, ok := it.next()
+			if !ok || t.After(horizon) {
+				break
+			}
+			ruleSet.RDate(t)
+		}
+	} else {
+		roption, err := recur.ROption(dateTime.Location())
+		if err != nil {
+			return nil, fmt.Errorf("ical: error parsing recurrence: %v", err)
+		}
+
+		rule := zonedRule{roption: *roption, wall: dtstart.Wall, loc: dateTime.Location()}
+		if err := rule.addTo(&ruleSet); err != nil {
+			return nil, fmt.Errorf("ical: error buildling rrule: %v", err)
+		}
+	}
 	ruleSet.DTStart(dateTime)
 
+	if err := comp.addRecurrenceDates(&ruleSet, loc, load); err != nil {
+		return nil, err
+	}
+	return &ruleSet, nil
+}
+
+// addRecurrenceDates adds the EXDATE and RDATE properties of the component to
+// a recurrence set.
+func (comp *Component) addRecurrenceDates(set *rrule.Set, loc *time.Location, load timezoneLoader) error {
 	for _, exdateProp := range comp.Props[PropExceptionDates] {
-		exdate, err := exdateProp.DateTime(loc)
+		exdates, err := exdateProp.dateTimeList(loc, load)
 		if err != nil {
-			return nil, fmt.Errorf("ical: error parsing exdate: %v", err)
+			return fmt.Errorf("ical: error parsing exdate: %v", err)
+		}
+		for _, exdate := range exdates {
+			set.ExDate(exdate)
 		}
-		ruleSet.ExDate(exdate)
 	}
 	for _, rdateProp := range comp.Props[PropRecurrenceDates] {
-		rdate, err := rdateProp.DateTime(loc)
+		rdates, err := rdateProp.dateTimeList(loc, load)
 		if err != nil {
-			return nil, fmt.Errorf("ical: error parsing rdate: %v", err)
+			return fmt.Errorf("ical: error parsing rdate: %v", err)
+		}
+		for _, rdate := range rdates {
+			set.RDate(rdate)
 		}
-		ruleSet.RDate(rdate)
 	}
-
-	return &ruleSet, nil
+	return nil
 }
 
 // NewCalendar creates a new calendar object.
@@ -60,6 +126,10 @@ func NewCalendar() *Calendar {
 }
 
 // Events extracts the list of events contained in the calendar.
+//
+// TZIDs defined by the VTIMEZONE components of the calendar can be resolved
+// by passing the calendar as the TimezoneResolver of Event.DateTimeStartWith,
+// Event.DateTimeEndWith and Component.RecurrenceSetWith.
 func (cal *Calendar) Events() []Event {
 	l := make([]Event, 0, len(cal.Children))
 	for _, child := range cal.Children {
@@ -81,14 +151,39 @@ func NewEvent() *Event {
 }
 
 // DateTimeStart returns the inclusive start of the event.
+//
+// The TZID is resolved with DefaultTimezoneResolver only: custom TZIDs
+// defined by a VTIMEZONE of the calendar, such as "Customized Time Zone",
+// fail to resolve. Use DateTimeStartWith with the Calendar containing the
+// event to resolve them.
 func (e *Event) DateTimeStart(loc *time.Location) (time.Time, error) {
-	return e.Props.DateTime(PropDateTimeStart, loc)
+	return e.DateTimeStartWith(loc, nil)
+}
+
+// DateTimeStartWith is like DateTimeStart, but resolves TZIDs with resolver,
+// e.g. the Calendar containing the event. A nil resolver uses
+// DefaultTimezoneResolver.
+func (e *Event) DateTimeStartWith(loc *time.Location, resolver TimezoneResolver) (time.Time, error) {
+	if prop := e.Props.Get(PropDateTimeStart); prop != nil {
+		return prop.dateTime(prop.Value, loc, resolverLoader(resolver))
+	}
+	return time.Time{}, nil
 }
 
 // DateTimeEnd returns the non-inclusive end of the event.
+//
+// Like DateTimeStart, custom TZIDs defined by a VTIMEZONE of the calendar
+// fail to resolve, use DateTimeEndWith to resolve them.
 func (e *Event) DateTimeEnd(loc *time.Location) (time.Time, error) {
+	return e.DateTimeEndWith(loc, nil)
+}
+
+// DateTimeEndWith is like DateTimeEnd, but resolves TZIDs with resolver, like
+// DateTimeStartWith.
+func (e *Event) DateTimeEndWith(loc *time.Location, resolver TimezoneResolver) (time.Time, error) {
+	load := resolverLoader(resolver)
 	if prop := e.Props.Get(PropDateTimeEnd); prop != nil {
-		return prop.DateTime(loc)
+		return prop.dateTime(prop.Value, loc, load)
 	}
 
 	startProp := e.Props.Get(PropDateTimeStart)
@@ -96,22 +191,22 @@ func (e *Event) DateTimeEnd(loc *time.Location) (time.Time, error) {
 		return time.Time{}, nil
 	}
 
-	start, err := startProp.DateTime(loc)
+	start, err := startProp.dateTime(startProp.Value, loc, load)
 	if err != nil {
 		return time.Time{}, err
 	}
 
-	var dur time.Duration
+	var dur Duration
 	if durProp := e.Props.Get(PropDuration); durProp != nil {
 		dur, err = durProp.Duration()
 		if err != nil {
 			return time.Time{}, err
 		}
 	} else if startProp.ValueType() == ValueDate {
-		dur = 24 * time.Hour
+		dur = Duration{Days: 1}
 	}
 
-	return start.Add(dur), nil
+	return dur.AddTo(start), nil
 }
 
 func (e *Event) Status() (EventStatus, error) {
@@ -135,3 +230,51 @@ func (e *Event) SetStatus(status EventStatus) {
 		e.Props.SetText(PropStatus, string(status))
 	}
 }
+
+// Attendees returns the list of participants of the event. Parameters are
+// parsed leniently: invalid enumerated values, such as PARTSTAT=COMPLETED,
+// are kept as-is and invalid address parameters are ignored. An error is only
+// returned if the calendar user address of an attendee is invalid. Use
+// Prop.Attendee to validate an attendee.
+func (e *Event) Attendees() ([]Attendee, error) {
+	props := e.Props.Values(PropAttendee)
+	l := make([]Attendee, 0, len(props))
+	for i := range props {
+		attendee, err := props[i].attendee(e.Name, true)
+		if err != nil {
+			return nil, err
+		}
+		l = append(l, *attendee)
+	}
+	return l, nil
+}
+
+// SetAttendees replaces the list of participants of the event.
+func (e *Event) SetAttendees(l []Attendee) {
+	e.Props.Del(PropAttendee)
+	for i := range l {
+		prop := NewProp(PropAttendee)
+		prop.SetAttendee(&l[i])
+		e.Props.Add(prop)
+	}
+}
+
+// Organizer returns the organizer of the event, or nil if there is none.
+func (e *Event) Organizer() (*Organizer, error) {
+	if prop := e.Props.Get(PropOrganizer); prop != nil {
+		return prop.Organizer()
+	}
+	return nil, nil
+}
+
+// SetOrganizer sets the organizer of the event. If organizer is nil, the
+// organizer is removed.
+func (e *Event) SetOrganizer(organizer *Organizer) {
+	if organizer == nil {
+		e.Props.Del(PropOrganizer)
+		return
+	}
+	prop := NewProp(PropOrganizer)
+	prop.SetOrganizer(organizer)
+	e.Props.Set(prop)
+}
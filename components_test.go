@@ -89,3 +89,98 @@ func TestRecurrenceSetWithRDate(t *testing.T) {
 		t.Errorf("RecurrenceSet did not process RDATE correctly.\n got: %v\nwant: %v", gotOccurrences, wantOccurrences)
 	}
 }
+
+func TestRecurrenceSetWithDateLists(t *testing.T) {
+	// A daily event with two exceptions in a single EXDATE property, and two
+	// extra occurrences in a single RDATE property using periods.
+	event := &Component{
+		Name: CompEvent,
+		Props: Props{
+			PropDateTimeStart: []Prop{{
+				Name:  PropDateTimeStart,
+				Value: "20240101T100000Z",
+			}},
+			PropRecurrenceRule: []Prop{{
+				Name:  PropRecurrenceRule,
+				Value: "FREQ=DAILY;COUNT=4",
+			}},
+			PropExceptionDates: []Prop{{
+				Name:  PropExceptionDates,
+				Value: "20240102T100000Z,20240104T100000Z",
+			}},
+			PropRecurrenceDates: []Prop{{
+				Name:   PropRecurrenceDates,
+				Params: Params{ParamValue: []string{string(ValuePeriod)}},
+				Value:  "20240110T100000Z/PT1H,20240111T120000Z/20240111T130000Z",
+			}},
+		},
+	}
+
+	gotRecurrenceSet, err := event.RecurrenceSet(time.UTC)
+	if err != nil {
+		t.Fatalf("Component.RecurrenceSet() returned an unexpected error: %v", err)
+	}
+
+	wantOccurrences := []time.Time{
+		time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 3, 10, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 10, 10, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 11, 12, 0, 0, 0, time.UTC),
+	}
+	if gotOccurrences := gotRecurrenceSet.All(); !reflect.DeepEqual(gotOccurrences, wantOccurrences) {
+		t.Errorf("RecurrenceSet did not process date lists correctly.\n got: %v\nwant: %v", gotOccurrences, wantOccurrences)
+	}
+}
+
+func TestEventDateTimeEndAcrossDST(t *testing.T) {
+	localTimezone, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event := NewEvent()
+	event.Props.SetDateTime(PropDateTimeStart, time.Date(2024, time.October, 26, 9, 0, 0, 0, localTimezone))
+	dur := NewProp(PropDuration)
+	if err := dur.SetDuration(Duration{Days: 1}); err != nil {
+		t.Fatal(err)
+	}
+	event.Props.Set(dur)
+
+	want := time.Date(2024, time.October, 27, 9, 0, 0, 0, localTimezone)
+	if got, err := event.DateTimeEnd(nil); err != nil {
+		t.Errorf("Event.DateTimeEnd() = %v", err)
+	} else if !got.Equal(want) {
+		t.Errorf("Event.DateTimeEnd() = %v, want %v", got, want)
+	}
+}
+
+func TestRecurrenceSetWithDateUntil(t *testing.T) {
+	event := &Component{
+		Name: CompEvent,
+		Props: Props{
+			PropDateTimeStart: []Prop{{
+				Name:   PropDateTimeStart,
+				Params: Params{ParamValue: []string{string(ValueDate)}},
+				Value:  "20240101",
+			}},
+			PropRecurrenceRule: []Prop{{
+				Name:  PropRecurrenceRule,
+				Value: "FREQ=DAILY;UNTIL=20240103;X-EXTENSION=ignored",
+			}},
+		},
+	}
+
+	gotRecurrenceSet, err := event.RecurrenceSet(time.UTC)
+	if err != nil {
+		t.Fatalf("Component.RecurrenceSet() returned an unexpected error: %v", err)
+	}
+
+	wantOccurrences := []time.Time{
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+	}
+	if gotOccurrences := gotRecurrenceSet.All(); !reflect.DeepEqual(gotOccurrences, wantOccurrences) {
+		t.Errorf("RecurrenceSet did not process UNTIL correctly.\n got: %v\nwant: %v", gotOccurrences, wantOccurrences)
+	}
+}
@@ -0,0 +1,471 @@
+package ical
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ConvertOptions contains options for Calendar.ConvertTimezones.
+type ConvertOptions struct {
+	// Floating is the location floating date-times are interpreted in. If
+	// nil, floating date-times are left alone.
+	Floating *time.Location
+	// Dates indicates whether DATE values are converted to DATE-TIME values
+	// at midnight in Floating. It has no effect if Floating is nil.
+	Dates bool
+	// Resolver resolves the TZIDs which aren't defined by a VTIMEZONE
+	// component of the calendar. If nil, DefaultTimezoneResolver is used.
+	Resolver TimezoneResolver
+}
+
+// convertedProps contains the names of the properties rewritten by
+// Calendar.ConvertTimezones, in addition to RRULE.
+var convertedProps = []string{
+	PropDateTimeStart,
+	PropDateTimeEnd,
+	PropDue,
+	PropRecurrenceID,
+	PropExceptionDates,
+	PropRecurrenceDates,
+}
+
+// ConvertTimezones rewrites the DTSTART, DTEND, DUE, RECURRENCE-ID, EXDATE and
+// RDATE properties of the calendar's components in target, as well as the
+// UNTIL part of RRULE. If target is UTC, date-times are written in UTC form,
+// otherwise they have a TZID parameter set to the name of target. TZIDs are
+// resolved with the VTIMEZONE components of the calendar, then with the
+// resolver of options. DATE values and floating date-times are left alone
+// unless requested in options, which may be nil.
+//
+// Recurrence rules are evaluated in target after the conversion, so the
+// BYxxx rule parts are shifted along with DTSTART, e.g. BYDAY=MO becomes
+// BYDAY=TU if DTSTART moves to the next day. The occurrences of a rule are
+// left unchanged: if the UTC offset of the original time zone changes
+// relative to target, the occurrences of a rule with a COUNT or an UNTIL
+// which would move are replaced with RDATE and EXDATE properties.
+//
+// Components whose rule can't be converted are left in their original time
+// zone: rules which can't be expressed in target, and rules without a COUNT
+// or an UNTIL whose occurrences would move, such as a weekly rule in a time
+// zone observing daylight saving time converted to UTC. They can be found by
+// looking for TZID parameters other than target.
+//
+// VTIMEZONE components which are no longer referenced are removed. A
+// VTIMEZONE component for target can be added with Encoder.AddTimezones. If
+// an error is returned, the calendar is left unchanged.
+func (cal *Calendar) ConvertTimezones(target *time.Location, options *ConvertOptions) error {
+	conv := timezoneConverter{target: target}
+	if options != nil {
+		conv.options = *options
+	}
+	conv.load = timezoneLoader(cal.Resolver(conv.options.Resolver).LoadLocation).cached()
+
+	// The calendar is only modified once all components have been converted
+	var updates []convertedComponent
+	if err := conv.convertComponent(cal.Component, &updates); err != nil {
+		return err
+	}
+
+	before := make(map[string]bool)
+	referencedTimezones(cal.Component, before)
+	for _, update := range updates {
+		update.comp.Props = update.props
+	}
+	after := make(map[string]bool)
+	referencedTimezones(cal.Component, after)
+
+	children := make([]*Component, 0, len(cal.Children))
+	for _, child := range cal.Children {
+		if child.Name == CompTimezone {
+			tzid, _ := child.Props.Text(PropTimezoneID)
+			if before[tzid] && !after[tzid] {
+				continue
+			}
+		}
+		children = append(children, child)
+	}
+	cal.Children = children
+
+	return nil
+}
+
+// ConvertTimezonesUTC is like ConvertTimezones with target set to UTC.
+func (cal *Calendar) ConvertTimezonesUTC(options *ConvertOptions) error {
+	return cal.ConvertTimezones(time.UTC, options)
+}
+
+// referencedTimezones collects the TZIDs referenced by a component and its
+// children, excluding VTIMEZONE components.
+func referencedTimezones(comp *Component, tzids map[string]bool) {
+	for _, props := range comp.Props {
+		for _, prop := range props {
+			if tzid := prop.Params.Get(PropTimezoneID); tzid != "" {
+				tzids[tzid] = true
+			}
+		}
+	}
+	for _, child := range comp.Children {
+		if child.Name != CompTimezone {
+			referencedTimezones(child, tzids)
+		}
+	}
+}
+
+type convertedComponent struct {
+	comp  *Component
+	props Props
+}
+
+type timezoneConverter struct {
+	target  *time.Location
+	options ConvertOptions
+	load    timezoneLoader
+}
+
+func (conv *timezoneConverter) convertComponent(comp *Component, updates *[]convertedComponent) error {
+	if comp.Name == CompTimezone {
+		return nil
+	}
+
+	props, err := conv.convertProps(comp)
+	if err != nil {
+		return fmt.Errorf("ical: failed to convert %v: %v", comp.Name, err)
+	} else if props != nil {
+		*updates = append(*updates, convertedComponent{comp, props})
+	}
+
+	for _, child := range comp.Children {
+		if err := conv.convertComponent(child, updates); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// convertProps returns the converted properties of a component, or nil if
+// none of them need to be converted.
+func (conv *timezoneConverter) convertProps(comp *Component) (Props, error) {
+	props := make(Props, len(comp.Props))
+	for name, l := range comp.Props {
+		props[name] = l
+	}
+
+	changed := false
+	for _, name := range convertedProps {
+		l := comp.Props[name]
+		converted := make([]Prop, len(l))
+		for i := range l {
+			prop, err := conv.convertProp(&l[i])
+			if err != nil {
+				return nil, err
+			} else if prop == nil {
+				converted[i] = l[i]
+				continue
+			}
+			converted[i] = *prop
+			changed = true
+		}
+		if len(l) > 0 {
+			props[name] = converted
+		}
+	}
+	if !changed {
+		return nil, nil
+	}
+
+	dtstart := comp.Props.Get(PropDateTimeStart)
+	if dtstart == nil {
+		return props, nil
+	}
+	loc, err := conv.location(dtstart)
+	if err != nil || loc == nil {
+		return props, err
+	}
+	start, err := dtstart.dateTime(dtstart.Value, loc, conv.load)
+	if err != nil {
+		return nil, err
+	}
+
+	if recur, err := comp.Props.Recur(); err != nil {
+		return nil, err
+	} else if recur != nil {
+		// convertRecur modifies the rule parts in place
+		orig, err := comp.Props.Recur()
+		if err != nil {
+			return nil, err
+		}
+		// Components whose rule can't be converted are left alone
+		if err := conv.convertRecur(recur, start); err != nil {
+			return nil, nil
+		}
+		prop := NewProp(PropRecurrenceRule)
+		prop.SetRecur(recur)
+		props[PropRecurrenceRule] = []Prop{*prop}
+
+		dt, err := dtstart.DateTimeValue()
+		if err != nil {
+			return nil, err
+		}
+		if ok, err := conv.keepOccurrences(props, orig, recur, dt.Wall, start); err != nil {
+			return nil, fmt.Errorf("cannot convert recurrence rule to %v: %v", conv.target, err)
+		} else if !ok {
+			return nil, nil
+		}
+	}
+
+	// Events starting on a DATE last one day by default
+	if comp.Name == CompEvent && dtstart.isDate() && props.Get(PropDateTimeEnd) == nil && props.Get(PropDuration) == nil {
+		prop := NewProp(PropDuration)
+		if err := prop.SetDuration(Duration{Days: 1}); err != nil {
+			return nil, err
+		}
+		props[PropDuration] = []Prop{*prop}
+	}
+
+	return props, nil
+}
+
+// location returns the location the property value is interpreted in, or nil
+// if the property is left alone.
+func (conv *timezoneConverter) location(prop *Prop) (*time.Location, error) {
+	switch {
+	case prop.isDate():
+		if !conv.options.Dates {
+			return nil, nil
+		}
+		return conv.options.Floating, nil
+	case prop.Params.Get(PropTimezoneID) != "":
+		return conv.load.load(prop.Params.Get(PropTimezoneID))
+	}
+
+	first := prop.Value
+	if i := strings.IndexAny(first, ",/"); i >= 0 {
+		first = first[:i]
+	}
+	if strings.HasSuffix(strings.ToUpper(first), "Z") {
+		return time.UTC, nil
+	}
+	return conv.options.Floating, nil
+}
+
+// convertProp returns the converted property, or nil if the property is left
+// alone.
+func (conv *timezoneConverter) convertProp(prop *Prop) (*Prop, error) {
+	loc, err := conv.location(prop)
+	if err != nil || loc == nil {
+		return nil, err
+	}
+
+	converted := &Prop{Name: prop.Name, Params: make(Params, len(prop.Params)), Value: prop.Value}
+	for name, values := range prop.Params {
+		converted.Params[name] = append([]string(nil), values...)
+	}
+
+	if prop.ValueType() == ValuePeriod {
+		l, err := prop.periodList(loc, conv.load)
+		if err != nil {
+			return nil, err
+		}
+		for i := range l {
+			l[i].Start = l[i].Start.In(conv.target)
+			if !l[i].End.IsZero() {
+				l[i].End = l[i].End.In(conv.target)
+			}
+		}
+		if err := converted.SetPeriodList(l); err != nil {
+			return nil, err
+		}
+	} else {
+		l, err := prop.dateTimeList(loc, conv.load)
+		if err != nil {
+			return nil, err
+		}
+		for i := range l {
+			l[i] = l[i].In(conv.target)
+		}
+		converted.SetDateTimeList(l)
+	}
+	return converted, nil
+}
+
+// convertRecur rewrites a recurrence rule starting at start, to be evaluated
+// in the target location.
+func (conv *timezoneConverter) convertRecur(r *Recur, start time.Time) error {
+	// UNTIL is in UTC if DTSTART has a TZID or is in UTC
+	if r.UntilDate || r.UntilFloating {
+		r.Until = r.until(start.Location()).UTC()
+		r.UntilDate, r.UntilFloating = false, false
+	}
+	return r.shiftWallClock(wallClock(start), wallClock(start.In(conv.target)))
+}
+
+// keepOccurrences adds RDATE and EXDATE properties so that the occurrences
+// of the converted rule, evaluated in the target location, are the ones of
+// the original rule. They differ when the UTC offset of the original location
+// changes relative to the target location. This is only possible for rules
+// with a COUNT or an UNTIL, false is returned for other rules.
+func (conv *timezoneConverter) keepOccurrences(props Props, orig, converted *Recur, wall, start time.Time) (bool, error) {
+	if orig.Count == 0 && orig.Until.IsZero() {
+		return !offsetChanges(start.Location(), conv.target, start), nil
+	}
+
+	want, err := orig.occurrences(wall, start.Location(), time.Time{})
+	if err != nil {
+		return false, err
+	}
+	inTarget := start.In(conv.target)
+	got, err := converted.occurrences(wallClock(inTarget), conv.target, time.Time{})
+	if err != nil {
+		return false, err
+	}
+
+	var rdates, exdates []time.Time
+	w, wok := want()
+	g, gok := got()
+	for wok || gok {
+		switch {
+		case wok && gok && w.Equal(g):
+			w, wok = want()
+			g, gok = got()
+		case wok && (!gok || w.Before(g)):
+			rdates = append(rdates, w.In(conv.target))
+			w, wok = want()
+		default:
+			exdates = append(exdates, g.In(conv.target))
+			g, gok = got()
+		}
+	}
+
+	if len(rdates) > 0 {
+		prop := NewProp(PropRecurrenceDates)
+		prop.SetDateTimeList(rdates)
+		props[PropRecurrenceDates] = append(props[PropRecurrenceDates], *prop)
+	}
+	if len(exdates) > 0 {
+		prop := NewProp(PropExceptionDates)
+		prop.SetDateTimeList(exdates)
+		props[PropExceptionDates] = append(props[PropExceptionDates], *prop)
+	}
+	return true, nil
+}
+
+// offsetChanges reports whether the difference between the UTC offsets of
+// two locations changes after start. The time zone database doesn't describe
+// the distant future, so changes are only looked up until two years after
+// start or after now, whichever is later.
+func offsetChanges(a, b *time.Location, start time.Time) bool {
+	diff := func(t time.Time) int {
+		_, offsetA := t.In(a).Zone()
+		_, offsetB := t.In(b).Zone()
+		return offsetA - offsetB
+	}
+
+	end := time.Now()
+	if start.After(end) {
+		end = start
+	}
+	end = end.AddDate(2, 0, 0)
+
+	d := diff(start)
+	for _, loc := range []*time.Location{a, b} {
+		for _, tr := range zoneTransitions(loc, start, end) {
+			if diff(tr.at) != d {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// shiftWallClock rewrites the rule parts of r after the wall-clock time of
+// DTSTART moved from "from" to "to", so that all occurrences are shifted by
+// the same amount.
+func (r *Recur) shiftWallClock(from, to time.Time) error {
+	shift := to.Sub(from)
+	if shift == 0 {
+		return nil
+	}
+	day := 24 * time.Hour
+	days := int(to.Truncate(day).Sub(from.Truncate(day)) / day)
+
+	subDaily := false
+	switch r.Freq {
+	case FrequencyHourly, FrequencyMinutely, FrequencySecondly:
+		subDaily = true
+	}
+	byDate := len(r.ByDay) > 0 || len(r.ByMonthDay) > 0 || len(r.ByYearDay) > 0 || len(r.ByWeekNo) > 0 || len(r.ByMonth) > 0
+
+	if len(r.ByHour) > 0 || len(r.ByMinute) > 0 || len(r.BySecond) > 0 {
+		if shift%time.Hour != 0 {
+			return fmt.Errorf("BYHOUR, BYMINUTE and BYSECOND can't be shifted by %v", shift)
+		}
+	}
+	if len(r.ByHour) > 0 {
+		hours := int(shift / time.Hour)
+		for i, h := range r.ByHour {
+			if floorDiv(h+hours, 24) != days {
+				return fmt.Errorf("BYHOUR=%v is shifted to another day than DTSTART", h)
+			}
+			r.ByHour[i] = mod(h+hours, 24)
+		}
+	} else if subDaily && byDate {
+		return fmt.Errorf("occurrences are shifted to another day")
+	}
+
+	if days == 0 {
+		return nil
+	}
+
+	if r.RScale != "" && r.RScale != "GREGORIAN" {
+		return fmt.Errorf("dates in the %v calendar can't be shifted", r.RScale)
+	}
+	if len(r.ByYearDay) > 0 || len(r.ByWeekNo) > 0 || len(r.BySetPos) > 0 {
+		return fmt.Errorf("BYYEARDAY, BYWEEKNO and BYSETPOS can't be shifted to another day")
+	}
+	if len(r.ByMonth) > 0 && (len(r.ByDay) > 0 || r.Freq == FrequencyDaily || r.Freq == FrequencyWeekly) {
+		return fmt.Errorf("BYMONTH can't be shifted to another day")
+	}
+
+	for i, wd := range r.ByDay {
+		if wd.N != 0 {
+			return fmt.Errorf("BYDAY=%v can't be shifted to another day", wd)
+		}
+		r.ByDay[i].Day = shiftWeekday(wd.Day, days)
+	}
+	if r.Freq == FrequencyWeekly && r.Interval > 1 {
+		// Keep the same weeks
+		wkst := r.WeekStart
+		if wkst == "" {
+			wkst = Monday
+		}
+		r.WeekStart = shiftWeekday(wkst, days)
+	}
+
+	for i, d := range r.ByMonthDay {
+		shifted := d + days
+		if (d > 0 && (shifted < 1 || shifted > 28)) || (d < 0 && (shifted > -1 || shifted < -28)) {
+			return fmt.Errorf("BYMONTHDAY=%v can't be shifted to another month", d)
+		}
+		r.ByMonthDay[i] = shifted
+	}
+
+	// Without BYDAY and BYMONTHDAY, the day of the month is the one of
+	// DTSTART
+	implicitMonthDay := (r.Freq == FrequencyMonthly || r.Freq == FrequencyYearly) && len(r.ByDay) == 0 && len(r.ByMonthDay) == 0
+	if implicitMonthDay && (from.Day() > 28 || to.Day() > 28) {
+		return fmt.Errorf("the day of the month of DTSTART can't be shifted")
+	}
+
+	return nil
+}
+
+func shiftWeekday(wd Weekday, days int) Weekday {
+	shifted := time.Weekday(mod(int(weekdays[wd])+days, 7))
+	for day, weekday := range weekdays {
+		if weekday == shifted {
+			return day
+		}
+	}
+	panic("unreachable")
+}
@@ -0,0 +1,316 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCalendarConvertTimezones(t *testing.T) {
+	cal, err := NewDecoder(strings.NewReader(customTimezoneCalendarStr)).Decode()
+	if err != nil {
+		t.Fatalf("Decode() = %v", err)
+	}
+	if err := cal.ConvertTimezonesUTC(nil); err != nil {
+		t.Fatalf("Calendar.ConvertTimezonesUTC() = %v", err)
+	}
+
+	for _, child := range cal.Children {
+		if child.Name == CompTimezone {
+			t.Errorf("Calendar.ConvertTimezonesUTC() kept VTIMEZONE %v", child.Props.Get(PropTimezoneID).Value)
+		}
+	}
+
+	events := cal.Events()
+	want := []map[string]string{
+		{
+			PropDateTimeStart:  "20240715T080000Z",
+			PropDateTimeEnd:    "20240715T090000Z",
+			PropExceptionDates: "20240915T080000Z",
+			PropRecurrenceRule: "FREQ=MONTHLY;COUNT=5",
+			PropDateTimeStamp:  "20240101T000000Z",
+			PropUID:            "custom@example.org",
+		},
+		{
+			PropDateTimeStart: "20240315T090000Z",
+			PropDateTimeEnd:   "20240615T080000Z",
+		},
+		{
+			PropDateTimeStart: "20240715T140000Z",
+		},
+	}
+	for i, props := range want {
+		for name, value := range props {
+			prop := events[i].Props.Get(name)
+			if prop == nil || prop.Value != value || prop.Params.Get(PropTimezoneID) != "" {
+				t.Errorf("event %v: %v = %#v, want %v", i, name, prop, value)
+			}
+		}
+	}
+
+	// The last occurrence is after the end of DST
+	if l := events[0].Props[PropRecurrenceDates]; len(l) != 1 || l[0].Value != "20241115T090000Z" {
+		t.Errorf("event 0: RDATE = %v, want 20241115T090000Z", l)
+	}
+	if l := events[0].Props[PropExceptionDates]; len(l) != 2 || l[1].Value != "20241115T080000Z" {
+		t.Errorf("event 0: EXDATE = %v, want 20240915T080000Z and 20241115T080000Z", l)
+	}
+}
+
+func TestCalendarConvertTimezonesTarget(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+
+	cal := NewCalendar()
+	event := NewEvent()
+	event.Props.SetDateTime(PropDateTimeStart, time.Date(2024, 1, 1, 23, 30, 0, 0, time.UTC))
+	exdate := NewProp(PropExceptionDates)
+	exdate.SetDateTimeList([]time.Time{
+		time.Date(2024, 1, 3, 23, 30, 0, 0, time.UTC),
+		time.Date(2024, 1, 8, 23, 30, 0, 0, time.UTC),
+	})
+	event.Props.Set(exdate)
+	event.Props.Set(&Prop{
+		Name:   PropRecurrenceRule,
+		Params: make(Params),
+		Value:  "FREQ=WEEKLY;BYDAY=MO,WE;BYHOUR=23;UNTIL=20240131T000000Z",
+	})
+	cal.Children = append(cal.Children, event.Component)
+
+	if err := cal.ConvertTimezones(paris, nil); err != nil {
+		t.Fatalf("Calendar.ConvertTimezones() = %v", err)
+	}
+
+	for name, want := range map[string]string{
+		PropDateTimeStart:  "20240102T003000",
+		PropExceptionDates: "20240104T003000,20240109T003000",
+		PropRecurrenceRule: "FREQ=WEEKLY;BYDAY=TU,TH;BYHOUR=0;UNTIL=20240131T000000Z",
+	} {
+		prop := event.Props.Get(name)
+		if prop.Value != want {
+			t.Errorf("%v = %v, want %v", name, prop.Value, want)
+		}
+		if name != PropRecurrenceRule && prop.Params.Get(PropTimezoneID) != "Europe/Paris" {
+			t.Errorf("%v has TZID %q, want Europe/Paris", name, prop.Params.Get(PropTimezoneID))
+		}
+	}
+
+	set, err := event.RecurrenceSet(nil)
+	if err != nil {
+		t.Fatalf("Event.RecurrenceSet() = %v", err)
+	}
+	if got := set.All(); len(got) != 7 || !got[0].Equal(time.Date(2024, 1, 1, 23, 30, 0, 0, time.UTC)) {
+		t.Errorf("Event.RecurrenceSet().All() = %v", got)
+	}
+}
+
+func TestCalendarConvertTimezonesFloating(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+
+	newCalendar := func() (*Calendar, *Event, *Event) {
+		cal := NewCalendar()
+		floating := NewEvent()
+		floating.Props.Set(&Prop{Name: PropDateTimeStart, Params: make(Params), Value: "20240101T090000"})
+		floating.Props.Set(&Prop{Name: PropRecurrenceRule, Params: make(Params), Value: "FREQ=DAILY;UNTIL=20240105T090000"})
+		allDay := NewEvent()
+		allDay.Props.SetDate(PropDateTimeStart, time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC))
+		cal.Children = append(cal.Children, floating.Component, allDay.Component)
+		return cal, floating, allDay
+	}
+
+	cal, floating, allDay := newCalendar()
+	if err := cal.ConvertTimezonesUTC(nil); err != nil {
+		t.Fatalf("Calendar.ConvertTimezonesUTC() = %v", err)
+	}
+	if got := floating.Props.Get(PropDateTimeStart).Value; got != "20240101T090000" {
+		t.Errorf("floating DTSTART = %v, want it unchanged", got)
+	}
+	if got := allDay.Props.Get(PropDateTimeStart).Value; got != "20240701" {
+		t.Errorf("DATE DTSTART = %v, want it unchanged", got)
+	}
+
+	cal, floating, allDay = newCalendar()
+	if err := cal.ConvertTimezonesUTC(&ConvertOptions{Floating: paris}); err != nil {
+		t.Fatalf("Calendar.ConvertTimezonesUTC() = %v", err)
+	}
+	if got := floating.Props.Get(PropDateTimeStart).Value; got != "20240101T080000Z" {
+		t.Errorf("floating DTSTART = %v, want 20240101T080000Z", got)
+	}
+	if got := floating.Props.Get(PropRecurrenceRule).Value; got != "FREQ=DAILY;UNTIL=20240105T080000Z" {
+		t.Errorf("floating RRULE = %v, want FREQ=DAILY;UNTIL=20240105T080000Z", got)
+	}
+	if got := allDay.Props.Get(PropDateTimeStart).Value; got != "20240701" {
+		t.Errorf("DATE DTSTART = %v, want it unchanged", got)
+	}
+
+	cal, _, allDay = newCalendar()
+	if err := cal.ConvertTimezonesUTC(&ConvertOptions{Floating: paris, Dates: true}); err != nil {
+		t.Fatalf("Calendar.ConvertTimezonesUTC() = %v", err)
+	}
+	if prop := allDay.Props.Get(PropDateTimeStart); prop.Value != "20240630T220000Z" || prop.Params.Get(ParamValue) != "" {
+		t.Errorf("DATE DTSTART = %#v, want 20240630T220000Z", prop)
+	}
+	if end, err := allDay.DateTimeEnd(nil); err != nil || !end.Equal(time.Date(2024, 7, 1, 22, 0, 0, 0, time.UTC)) {
+		t.Errorf("Event.DateTimeEnd() = %v, %v, want 20240701T220000Z", end, err)
+	}
+}
+
+func TestCalendarConvertTimezonesUnsupportedRule(t *testing.T) {
+	cal, err := NewDecoder(strings.NewReader(customTimezoneCalendarStr)).Decode()
+	if err != nil {
+		t.Fatalf("Decode() = %v", err)
+	}
+	event := cal.Events()[2]
+	event.Props.Set(&Prop{
+		Name:   PropDateTimeStart,
+		Params: Params{PropTimezoneID: []string{"America/New_York"}},
+		Value:  "20240310T210000",
+	})
+	event.Props.Set(&Prop{
+		Name:   PropRecurrenceRule,
+		Params: make(Params),
+		Value:  "FREQ=MONTHLY;BYDAY=2SU",
+	})
+
+	// The event is left alone, the others are converted
+	if err := cal.ConvertTimezonesUTC(nil); err != nil {
+		t.Fatalf("Calendar.ConvertTimezonesUTC() = %v", err)
+	}
+	events := cal.Events()
+	if prop := events[2].Props.Get(PropDateTimeStart); prop.Value != "20240310T210000" || prop.Params.Get(PropTimezoneID) != "America/New_York" {
+		t.Errorf("Calendar.ConvertTimezonesUTC() converted the event: DTSTART = %#v", prop)
+	}
+	if got := events[2].Props.Get(PropRecurrenceRule).Value; got != "FREQ=MONTHLY;BYDAY=2SU" {
+		t.Errorf("Calendar.ConvertTimezonesUTC() converted the event: RRULE = %v", got)
+	}
+	if got := events[0].Props.Get(PropDateTimeStart).Value; got != "20240715T080000Z" {
+		t.Errorf("DTSTART = %v, want 20240715T080000Z", got)
+	}
+	if len(cal.Children) != 3 {
+		t.Errorf("Calendar.ConvertTimezonesUTC() kept %v children, want 3", len(cal.Children))
+	}
+}
+
+func TestCalendarConvertTimezonesOffsetChange(t *testing.T) {
+	if _, err := time.LoadLocation("America/New_York"); err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+
+	newCalendar := func(rrule string) (*Calendar, *Event) {
+		cal := NewCalendar()
+		event := NewEvent()
+		event.Props.Set(&Prop{
+			Name:   PropDateTimeStart,
+			Params: Params{PropTimezoneID: []string{"America/New_York"}},
+			Value:  "20240202T090000",
+		})
+		event.Props.Set(&Prop{Name: PropRecurrenceRule, Params: make(Params), Value: rrule})
+		cal.Children = append(cal.Children, event.Component)
+		return cal, event
+	}
+	occurrences := func(event *Event) []time.Time {
+		it, err := event.RecurrenceIterator(nil, nil)
+		if err != nil {
+			t.Fatalf("Event.RecurrenceIterator() = %v", err)
+		}
+		var l []time.Time
+		for t, ok := it.Next(); ok; t, ok = it.Next() {
+			l = append(l, t)
+		}
+		return l
+	}
+
+	// Rules with a COUNT or an UNTIL keep their occurrences
+	for _, rrule := range []string{"FREQ=WEEKLY;COUNT=30", "FREQ=WEEKLY;UNTIL=20241231T000000Z"} {
+		cal, event := newCalendar(rrule)
+		want := occurrences(event)
+		if err := cal.ConvertTimezonesUTC(nil); err != nil {
+			t.Fatalf("Calendar.ConvertTimezonesUTC() = %v", err)
+		}
+		got := occurrences(event)
+		if len(got) != len(want) {
+			t.Fatalf("%v: converted event has %v occurrences, want %v", rrule, len(got), len(want))
+		}
+		for i := range want {
+			if !got[i].Equal(want[i]) {
+				t.Errorf("%v: occurrence %v = %v, want %v", rrule, i, got[i], want[i])
+			}
+		}
+		if got := want[18]; !got.Equal(time.Date(2024, 6, 7, 13, 0, 0, 0, time.UTC)) {
+			t.Errorf("%v: occurrence 18 = %v, want 2024-06-07 13:00 UTC", rrule, got)
+		}
+		if event.Props.Get(PropRecurrenceDates) == nil || event.Props.Get(PropExceptionDates) == nil {
+			t.Errorf("%v: converted event has no RDATE or EXDATE", rrule)
+		}
+	}
+
+	// Other rules can only be converted if the offset doesn't change
+	if _, err := time.LoadLocation("Asia/Tokyo"); err == nil {
+		cal, event := newCalendar("FREQ=WEEKLY")
+		event.Props.Get(PropDateTimeStart).Params.Set(PropTimezoneID, "Asia/Tokyo")
+		if err := cal.ConvertTimezonesUTC(nil); err != nil {
+			t.Errorf("Calendar.ConvertTimezonesUTC() = %v", err)
+		} else if got := event.Props.Get(PropDateTimeStart).Value; got != "20240202T000000Z" {
+			t.Errorf("DTSTART = %v, want 20240202T000000Z", got)
+		}
+	}
+	cal, event := newCalendar("FREQ=WEEKLY")
+	if err := cal.ConvertTimezonesUTC(nil); err != nil {
+		t.Errorf("Calendar.ConvertTimezonesUTC() = %v", err)
+	}
+	if got := event.Props.Get(PropDateTimeStart).Value; got != "20240202T090000" {
+		t.Errorf("Calendar.ConvertTimezonesUTC() converted the event: DTSTART = %v", got)
+	}
+}
+
+func TestCalendarConvertTimezonesUnbounded(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+
+	cal := NewCalendar()
+	tz := NewTimezone(paris, time.Date(2024, 1, 1, 0, 0, 0, 0, paris), time.Date(2025, 1, 1, 0, 0, 0, 0, paris))
+	weekly := NewEvent()
+	weekly.Props.SetDateTime(PropDateTimeStart, time.Date(2024, 1, 8, 10, 0, 0, 0, paris))
+	weekly.Props.SetDateTime(PropDateTimeEnd, time.Date(2024, 1, 8, 11, 0, 0, 0, paris))
+	weekly.Props.Set(&Prop{Name: PropRecurrenceRule, Params: make(Params), Value: "FREQ=WEEKLY"})
+	single := NewEvent()
+	single.Props.SetDateTime(PropDateTimeStart, time.Date(2024, 7, 15, 10, 0, 0, 0, paris))
+	single.Props.SetDateTime(PropDateTimeEnd, time.Date(2024, 7, 15, 11, 0, 0, 0, paris))
+	cal.Children = append(cal.Children, tz, weekly.Component, single.Component)
+
+	if err := cal.ConvertTimezonesUTC(nil); err != nil {
+		t.Fatalf("Calendar.ConvertTimezonesUTC() = %v", err)
+	}
+
+	for name, want := range map[string]string{
+		PropDateTimeStart:  "20240108T100000",
+		PropDateTimeEnd:    "20240108T110000",
+		PropRecurrenceRule: "FREQ=WEEKLY",
+	} {
+		prop := weekly.Props.Get(name)
+		if prop.Value != want {
+			t.Errorf("weekly %v = %v, want %v", name, prop.Value, want)
+		}
+		if name != PropRecurrenceRule && prop.Params.Get(PropTimezoneID) != "Europe/Paris" {
+			t.Errorf("weekly %v has TZID %q, want Europe/Paris", name, prop.Params.Get(PropTimezoneID))
+		}
+	}
+	for name, want := range map[string]string{
+		PropDateTimeStart: "20240715T080000Z",
+		PropDateTimeEnd:   "20240715T090000Z",
+	} {
+		if prop := single.Props.Get(name); prop.Value != want || prop.Params.Get(PropTimezoneID) != "" {
+			t.Errorf("single %v = %#v, want %v", name, prop, want)
+		}
+	}
+	if len(cal.Children) != 3 || cal.Children[0] != tz {
+		t.Errorf("Calendar.ConvertTimezonesUTC() removed the VTIMEZONE still in use")
+	}
+}
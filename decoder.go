@@ -66,7 +66,38 @@ func (ld *lineDecoder) decodeParamValue() (string, error) {
 		}
 	}
 
-	return v, nil
+	return decodeParamCaret(v), nil
+}
+
+// decodeParamCaret decodes the caret escape sequences defined in RFC 6868.
+func decodeParamCaret(v string) string {
+	if !strings.ContainsRune(v, '^') {
+		return v
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		if c == '^' && i+1 < len(v) {
+			switch v[i+1] {
+			case 'n':
+				sb.WriteByte('\n')
+				i++
+				continue
+			case '^':
+				sb.WriteByte('^')
+				i++
+				continue
+			case '\'':
+				sb.WriteByte('"')
+				i++
+				continue
+			}
+		}
+		// Other sequences are left as-is
+		sb.WriteByte(c)
+	}
+	return sb.String()
 }
 
 func (ld *lineDecoder) decodeParam() (string, []string, error) {
@@ -91,3 +91,20 @@ END:VCALENDAR
 		t.Errorf("DecodeCalendar() = \n%#v\nbut want:\n%#v", cal, calendar)
 	}
 }
+
+func TestDecoderParamCaret(t *testing.T) {
+	ld := lineDecoder{`ATTENDEE;CN="George Herman ^'Babe^' Ruth";X-ADDRESS=Main St.^nSpringfield^^;X-OTHER=a^b:mailto:babe@example.org`}
+	prop, err := ld.decodeContentLine()
+	if err != nil {
+		t.Fatalf("decodeContentLine() = %v", err)
+	}
+
+	want := Params{
+		"CN":        []string{`George Herman "Babe" Ruth`},
+		"X-ADDRESS": []string{"Main St.\nSpringfield^"},
+		"X-OTHER":   []string{"a^b"},
+	}
+	if !reflect.DeepEqual(prop.Params, want) {
+		t.Errorf("decodeContentLine() params = %#v, want %#v", prop.Params, want)
+	}
+}
@@ -165,15 +165,48 @@ func checkComponent(comp *Component) error {
 		}
 	}
 
+	// Observances are excluded: their UNTIL is in UTC while their DTSTART is
+	// a local time
+	switch comp.Name {
+	case CompEvent, CompToDo, CompJournal:
+		r, err := comp.Props.Recur()
+		if err != nil {
+			return fmt.Errorf("ical: failed to encode %q: %v", comp.Name, err)
+		} else if r != nil {
+			if err := r.CheckStart(comp.Props.Get(PropDateTimeStart)); err != nil {
+				return fmt.Errorf("ical: failed to encode %q: %v", comp.Name, err)
+			}
+		}
+	}
+
 	return nil
 }
 
 type Encoder struct {
+	// AddTimezones enables the generation of VTIMEZONE components for the
+	// TZIDs referenced in encoded calendars, if they aren't already defined.
+	// The components are generated from the system time zone database, see
+	// NewTimezone.
+	AddTimezones bool
+
 	w io.Writer
 }
 
 func NewEncoder(w io.Writer) *Encoder {
-	return &Encoder{w}
+	return &Encoder{w: w}
+}
+
+var paramCaretReplacer = strings.NewReplacer(
+	"^", "^^",
+	"\r\n", "^n",
+	"\n", "^n",
+	`"`, "^'",
+)
+
+// encodeParamCaret encodes a parameter value with the caret escape sequences
+// defined in RFC 6868.
+func encodeParamCaret(v string) string {
+	return paramCaretReplacer.Replace(v)
 }
 
 func (enc *Encoder) encodeProp(prop *Prop) error {
@@ -195,9 +228,7 @@ func (enc *Encoder) encodeProp(prop *Prop) error {
 			if i > 0 {
 				buf.WriteString(",")
 			}
-			if strings.ContainsRune(v, '"') {
-				return fmt.Errorf("ical: failed to encode param value: contains a double-quote")
-			}
+			v = encodeParamCaret(v)
 			if strings.ContainsAny(v, ";:,") {
 				buf.WriteString(`"` + v + `"`)
 			} else {
@@ -250,6 +281,21 @@ func (enc *Encoder) encodeComponent(comp *Component) error {
 	return enc.encodeProp(&Prop{Name: "END", Value: comp.Name})
 }
 
+// Encode writes a calendar. An error is returned if the calendar is invalid,
+// e.g. if a component has a recurrence rule inconsistent with its DTSTART,
+// see Recur.CheckStart.
 func (enc *Encoder) Encode(cal *Calendar) error {
-	return enc.encodeComponent(cal.Component)
+	comp := cal.Component
+	if enc.AddTimezones {
+		timezones, err := cal.missingTimezones()
+		if err != nil {
+			return err
+		}
+		if len(timezones) > 0 {
+			withTimezones := *comp
+			withTimezones.Children = append(timezones, comp.Children...)
+			comp = &withTimezones
+		}
+	}
+	return enc.encodeComponent(comp)
 }
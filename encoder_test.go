@@ -2,7 +2,10 @@ package ical
 
 import (
 	"bytes"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestEncoder(t *testing.T) {
@@ -16,3 +19,61 @@ func TestEncoder(t *testing.T) {
 		t.Errorf("Encode() = \n%v\nbut want:\n%v", s, exampleCalendarStr)
 	}
 }
+
+func TestEncoderParamCaret(t *testing.T) {
+	prop := NewProp(PropLocation)
+	prop.Params.Set(ParamLabel, "Conference room\n\"Blue\" ^2")
+	prop.Value = "Room 2"
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).encodeProp(prop); err != nil {
+		t.Fatalf("encodeProp() = %v", err)
+	}
+
+	want := "LOCATION;LABEL=Conference room^n^'Blue^' ^^2:Room 2\r\n"
+	if s := buf.String(); s != want {
+		t.Errorf("encodeProp() = %q, want %q", s, want)
+	}
+
+	ld := lineDecoder{strings.TrimSuffix(want, "\r\n")}
+	decoded, err := ld.decodeContentLine()
+	if err != nil {
+		t.Fatalf("decodeContentLine() = %v", err)
+	}
+	if !reflect.DeepEqual(decoded, prop) {
+		t.Errorf("decodeContentLine() = %#v, want %#v", decoded, prop)
+	}
+}
+
+func TestEncoderRecurrence(t *testing.T) {
+	testCases := []struct {
+		rrule string
+		valid bool
+	}{
+		{"FREQ=DAILY;UNTIL=20240110T090000Z", true},
+		{"FREQ=DAILY;COUNT=10", true},
+		{"FREQ=DAILY;UNTIL=20240110", false},
+		{"FREQ=DAILY;UNTIL=20240110T090000", false},
+		{"FREQ=DAILY;BYDAY=XX", false},
+	}
+	for _, tCase := range testCases {
+		event := NewEvent()
+		event.Props.SetText(PropUID, "recurrence@example.org")
+		event.Props.SetDateTime(PropDateTimeStamp, time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC))
+		event.Props.SetDateTime(PropDateTimeStart, time.Date(2024, time.January, 1, 9, 0, 0, 0, time.UTC))
+		event.Props.Set(&Prop{Name: PropRecurrenceRule, Params: make(Params), Value: tCase.rrule})
+
+		cal := NewCalendar()
+		cal.Props.SetText(PropVersion, "2.0")
+		cal.Props.SetText(PropProductID, "-//xyz Corp//NONSGML PDA Calendar Version 1.0//EN")
+		cal.Children = append(cal.Children, event.Component)
+
+		var buf bytes.Buffer
+		err := NewEncoder(&buf).Encode(cal)
+		if tCase.valid && err != nil {
+			t.Errorf("Encode() with RRULE %v = %v", tCase.rrule, err)
+		} else if !tCase.valid && err == nil {
+			t.Errorf("Encode() with RRULE %v = nil, want an error", tCase.rrule)
+		}
+	}
+}
@@ -152,7 +152,7 @@ var defaultValueTypes = map[string]ValueType{
 	PropClass:              ValueText,
 	PropComment:            ValueText,
 	PropDescription:        ValueText,
-	PropGeo:                ValueFloat,
+	PropGeo:                ValueFloat, // structured: latitude;longitude
 	PropLocation:           ValueText,
 	PropPercentComplete:    ValueInt,
 	PropPriority:           ValueInt,
@@ -188,7 +188,7 @@ var defaultValueTypes = map[string]ValueType{
 	PropDateTimeStamp:      ValueDateTime,
 	PropLastModified:       ValueDateTime,
 	PropSequence:           ValueInt,
-	PropRequestStatus:      ValueText,
+	PropRequestStatus:      ValueText, // structured: code;description;extdata
 	PropName:               ValueText,
 	PropRefreshInterval:    ValueDuration,
 	PropSource:             ValueURI,
@@ -229,3 +229,201 @@ const (
 	ConferenceScreen    ConferenceFeature = "SCREEN"
 	ConferenceVideo     ConferenceFeature = "VIDEO"
 )
+
+// CalendarUserType is the type of a calendar user, defined in RFC 5545
+// section 3.2.3.
+type CalendarUserType string
+
+const (
+	CalendarUserIndividual CalendarUserType = "INDIVIDUAL"
+	CalendarUserGroup      CalendarUserType = "GROUP"
+	CalendarUserResource   CalendarUserType = "RESOURCE"
+	CalendarUserRoom       CalendarUserType = "ROOM"
+	CalendarUserUnknown    CalendarUserType = "UNKNOWN"
+)
+
+// FreeBusyType is the type of a free/busy time, defined in RFC 5545 section
+// 3.2.9.
+type FreeBusyType string
+
+const (
+	FreeBusyFree            FreeBusyType = "FREE"
+	FreeBusyBusy            FreeBusyType = "BUSY"
+	FreeBusyBusyUnavailable FreeBusyType = "BUSY-UNAVAILABLE"
+	FreeBusyBusyTentative   FreeBusyType = "BUSY-TENTATIVE"
+)
+
+// ParticipationStatus is the participation status of a calendar user,
+// defined in RFC 5545 section 3.2.12. The set of allowed values depends on
+// the component kind, see ParticipationStatus.ValidFor.
+type ParticipationStatus string
+
+const (
+	ParticipationNeedsAction ParticipationStatus = "NEEDS-ACTION"
+	ParticipationAccepted    ParticipationStatus = "ACCEPTED"
+	ParticipationDeclined    ParticipationStatus = "DECLINED"
+	ParticipationTentative   ParticipationStatus = "TENTATIVE"
+	ParticipationDelegated   ParticipationStatus = "DELEGATED"
+	ParticipationCompleted   ParticipationStatus = "COMPLETED"
+	ParticipationInProcess   ParticipationStatus = "IN-PROCESS"
+)
+
+var participationStatuses = map[string][]ParticipationStatus{
+	CompEvent: {
+		ParticipationNeedsAction,
+		ParticipationAccepted,
+		ParticipationDeclined,
+		ParticipationTentative,
+		ParticipationDelegated,
+	},
+	CompToDo: {
+		ParticipationNeedsAction,
+		ParticipationAccepted,
+		ParticipationDeclined,
+		ParticipationTentative,
+		ParticipationDelegated,
+		ParticipationCompleted,
+		ParticipationInProcess,
+	},
+	CompJournal: {
+		ParticipationNeedsAction,
+		ParticipationAccepted,
+		ParticipationDeclined,
+	},
+}
+
+// ValidFor checks whether the participation status is allowed in a component
+// of the specified kind. Extension values are allowed everywhere. If
+// compName is empty or isn't one of VEVENT, VTODO and VJOURNAL, all standard
+// values are allowed.
+func (status ParticipationStatus) ValidFor(compName string) bool {
+	allowed, ok := participationStatuses[compName]
+	if !ok {
+		allowed = participationStatuses[CompToDo]
+	}
+	for _, s := range allowed {
+		if status == s {
+			return true
+		}
+	}
+	// Reject standard values not allowed for this component
+	for _, s := range participationStatuses[CompToDo] {
+		if status == s {
+			return false
+		}
+	}
+	return isExtensionToken(string(status))
+}
+
+// RelationshipType is the type of a hierarchical relationship, defined in
+// RFC 5545 section 3.2.15.
+type RelationshipType string
+
+const (
+	RelationshipParent  RelationshipType = "PARENT"
+	RelationshipChild   RelationshipType = "CHILD"
+	RelationshipSibling RelationshipType = "SIBLING"
+)
+
+// Role is the participation role of a calendar user, defined in RFC 5545
+// section 3.2.16.
+type Role string
+
+const (
+	RoleChair          Role = "CHAIR"
+	RoleReqParticipant Role = "REQ-PARTICIPANT"
+	RoleOptParticipant Role = "OPT-PARTICIPANT"
+	RoleNonParticipant Role = "NON-PARTICIPANT"
+)
+
+// Related is the relationship of an alarm trigger to the start or the end of
+// its parent component, defined in RFC 5545 section 3.2.14.
+type Related string
+
+const (
+	RelatedStart Related = "START"
+	RelatedEnd   Related = "END"
+)
+
+// Range is the effective range of a recurrence identifier, defined in RFC
+// 5545 section 3.2.13.
+type Range string
+
+const (
+	RangeThisAndFuture Range = "THISANDFUTURE"
+)
+
+// RequestStatusCode is a hierarchical request status code, e.g. "2.0".
+// Defined in RFC 5545 section 3.8.8.3.
+type RequestStatusCode string
+
+// Request status codes defined in RFC 5546 section 3.6.
+const (
+	RequestStatusSuccess                     RequestStatusCode = "2.0"
+	RequestStatusFallback                    RequestStatusCode = "2.1"
+	RequestStatusInvalidPropertyIgnored      RequestStatusCode = "2.2"
+	RequestStatusInvalidParamIgnored         RequestStatusCode = "2.3"
+	RequestStatusUnknownPropertyIgnored      RequestStatusCode = "2.4"
+	RequestStatusUnknownPropertyValueIgnored RequestStatusCode = "2.5"
+	RequestStatusInvalidComponentIgnored     RequestStatusCode = "2.6"
+	RequestStatusForwarded                   RequestStatusCode = "2.7"
+	RequestStatusRepeatingEventIgnored       RequestStatusCode = "2.8"
+	RequestStatusTruncatedEnd                RequestStatusCode = "2.9"
+	RequestStatusRepeatingToDoIgnored        RequestStatusCode = "2.10"
+	RequestStatusRecurrenceClipped           RequestStatusCode = "2.11"
+	RequestStatusInvalidPropertyName         RequestStatusCode = "3.0"
+	RequestStatusInvalidPropertyValue        RequestStatusCode = "3.1"
+	RequestStatusInvalidParam                RequestStatusCode = "3.2"
+	RequestStatusInvalidParamValue           RequestStatusCode = "3.3"
+	RequestStatusInvalidComponentSequence    RequestStatusCode = "3.4"
+	RequestStatusInvalidDateTime             RequestStatusCode = "3.5"
+	RequestStatusInvalidRule                 RequestStatusCode = "3.6"
+	RequestStatusInvalidCalendarUser         RequestStatusCode = "3.7"
+	RequestStatusNoAuthority                 RequestStatusCode = "3.8"
+	RequestStatusUnsupportedVersion          RequestStatusCode = "3.9"
+	RequestStatusTooLarge                    RequestStatusCode = "3.10"
+	RequestStatusRequiredMissing             RequestStatusCode = "3.11"
+	RequestStatusUnknownFound                RequestStatusCode = "3.12"
+	RequestStatusUnsupportedFound            RequestStatusCode = "3.13"
+	RequestStatusUnsupportedCapability       RequestStatusCode = "3.14"
+	RequestStatusBusy                        RequestStatusCode = "4.0"
+	RequestStatusMaySupported                RequestStatusCode = "5.0"
+	RequestStatusServiceUnavailable          RequestStatusCode = "5.1"
+	RequestStatusInvalidCalendarService      RequestStatusCode = "5.2"
+	RequestStatusNoSchedulingSupport         RequestStatusCode = "5.3"
+)
+
+var requestStatusDescriptions = map[RequestStatusCode]string{
+	RequestStatusSuccess:                     "Success",
+	RequestStatusFallback:                    "Success, but fallback taken on one or more property values",
+	RequestStatusInvalidPropertyIgnored:      "Success; invalid property ignored",
+	RequestStatusInvalidParamIgnored:         "Success; invalid property parameter ignored",
+	RequestStatusUnknownPropertyIgnored:      "Success; unknown, non-standard property ignored",
+	RequestStatusUnknownPropertyValueIgnored: "Success; unknown, non-standard property value ignored",
+	RequestStatusInvalidComponentIgnored:     "Success; invalid calendar component ignored",
+	RequestStatusForwarded:                   "Success; request forwarded to Calendar User",
+	RequestStatusRepeatingEventIgnored:       "Success; repeating event ignored. Scheduled as a single component",
+	RequestStatusTruncatedEnd:                "Success; truncated end date time to date boundary",
+	RequestStatusRepeatingToDoIgnored:        "Success; repeating VTODO ignored. Scheduled as a single VTODO",
+	RequestStatusRecurrenceClipped:           "Success; unbounded RRULE clipped at some finite number of instances",
+	RequestStatusInvalidPropertyName:         "Invalid property name",
+	RequestStatusInvalidPropertyValue:        "Invalid property value",
+	RequestStatusInvalidParam:                "Invalid property parameter",
+	RequestStatusInvalidParamValue:           "Invalid property parameter value",
+	RequestStatusInvalidComponentSequence:    "Invalid calendar component sequence",
+	RequestStatusInvalidDateTime:             "Invalid date or time",
+	RequestStatusInvalidRule:                 "Invalid rule",
+	RequestStatusInvalidCalendarUser:         "Invalid Calendar User",
+	RequestStatusNoAuthority:                 "No authority",
+	RequestStatusUnsupportedVersion:          "Unsupported version",
+	RequestStatusTooLarge:                    "Request entity too large",
+	RequestStatusRequiredMissing:             "Required component or property missing",
+	RequestStatusUnknownFound:                "Unknown component or property found",
+	RequestStatusUnsupportedFound:            "Unsupported component or property found",
+	RequestStatusUnsupportedCapability:       "Unsupported capability",
+	RequestStatusBusy:                        "Event conflict; date/time is busy",
+	RequestStatusMaySupported:                "Request MAY supported",
+	RequestStatusServiceUnavailable:          "Service unavailable",
+	RequestStatusInvalidCalendarService:      "Invalid calendar service",
+	RequestStatusNoSchedulingSupport:         "No scheduling support for user",
+}
@@ -2,4 +2,7 @@ module github.com/emersion/go-ical
 
 go 1.13
 
-require github.com/teambition/rrule-go v1.8.2
+require (
+	github.com/teambition/rrule-go v1.8.2
+	golang.org/x/text v0.3.8
+)
@@ -0,0 +1,156 @@
+package ical
+
+// Hebrew calendar arithmetic, following "Calendrical Calculations" by
+// Reingold and Dershowitz. Internally months are numbered from Nisan (1) to
+// Adar II (13), whereas RFC 7529 numbers them from Tishri (1) to Elul (12),
+// Adar I being the leap month 5L.
+
+// Fixed day number of 1 Tishri AM 1.
+const hebrewEpoch = -1373427
+
+func hebrewLeapYear(year int) bool {
+	return mod(7*year+1, 19) < 7
+}
+
+func hebrewElapsedDays(year int) int {
+	monthsElapsed := floorDiv(235*year-234, 19)
+	partsElapsed := 12084 + 13753*int64(monthsElapsed)
+	days := 29*monthsElapsed + int(partsElapsed/25920)
+	if mod(3*(days+1), 7) < 3 {
+		return days + 1
+	}
+	return days
+}
+
+func hebrewYearLengthCorrection(year int) int {
+	ny0 := hebrewElapsedDays(year - 1)
+	ny1 := hebrewElapsedDays(year)
+	ny2 := hebrewElapsedDays(year + 1)
+	switch {
+	case ny2-ny1 == 356:
+		return 2
+	case ny1-ny0 == 382:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func hebrewNewYear(year int) int {
+	return hebrewEpoch + hebrewElapsedDays(year) + hebrewYearLengthCorrection(year)
+}
+
+func hebrewDaysInYear(year int) int {
+	return hebrewNewYear(year+1) - hebrewNewYear(year)
+}
+
+func hebrewLastMonth(year int) int {
+	if hebrewLeapYear(year) {
+		return 13
+	}
+	return 12
+}
+
+func hebrewDaysInMonth(year, month int) int {
+	switch month {
+	case 2, 4, 6, 10, 13:
+		return 29
+	case 12:
+		if !hebrewLeapYear(year) {
+			return 29
+		}
+	case 8:
+		// Marheshvan is long in complete years
+		if n := hebrewDaysInYear(year); n != 355 && n != 385 {
+			return 29
+		}
+	case 9:
+		// Kislev is short in deficient years
+		if n := hebrewDaysInYear(year); n == 353 || n == 383 {
+			return 29
+		}
+	}
+	return 30
+}
+
+func fixedFromHebrew(year, month, day int) int {
+	fixed := hebrewNewYear(year) + day - 1
+	if month < 7 {
+		for m := 7; m <= hebrewLastMonth(year); m++ {
+			fixed += hebrewDaysInMonth(year, m)
+		}
+		for m := 1; m < month; m++ {
+			fixed += hebrewDaysInMonth(year, m)
+		}
+	} else {
+		for m := 7; m < month; m++ {
+			fixed += hebrewDaysInMonth(year, m)
+		}
+	}
+	return fixed
+}
+
+func hebrewFromFixed(fixed int) (year, month, day int) {
+	approx := int(int64(fixed-hebrewEpoch)*98496/35975351) + 1
+	year = approx - 1
+	for hebrewNewYear(year+1) <= fixed {
+		year++
+	}
+
+	month = 1
+	if fixed < fixedFromHebrew(year, 1, 1) {
+		month = 7
+	}
+	for fixed > fixedFromHebrew(year, month, hebrewDaysInMonth(year, month)) {
+		month++
+	}
+
+	day = fixed - fixedFromHebrew(year, month, 1) + 1
+	return year, month, day
+}
+
+type hebrewCalendar struct{}
+
+// hebrewMonth converts an RFC 7529 month number to an internal one.
+func hebrewMonth(year int, month RecurMonth) int {
+	switch {
+	case month.Leap:
+		return 12
+	case month.Month <= 5:
+		return month.Month + 6
+	case month.Month == 6 && hebrewLeapYear(year):
+		return 13
+	case month.Month == 6:
+		return 12
+	default:
+		return month.Month - 6
+	}
+}
+
+func (hebrewCalendar) fromFixed(fixed int) calendarDate {
+	year, month, day := hebrewFromFixed(fixed)
+	date := calendarDate{Year: year, Day: day}
+	switch {
+	case month == 12 && hebrewLeapYear(year):
+		date.Month = RecurMonth{Month: 5, Leap: true}
+	case month >= 12:
+		date.Month = RecurMonth{Month: 6}
+	case month >= 7:
+		date.Month = RecurMonth{Month: month - 6}
+	default:
+		date.Month = RecurMonth{Month: month + 6}
+	}
+	return date
+}
+
+func (hebrewCalendar) monthStart(year int, month RecurMonth) int {
+	return fixedFromHebrew(year, hebrewMonth(year, month), 1)
+}
+
+func (hebrewCalendar) monthLength(year int, month RecurMonth) int {
+	return hebrewDaysInMonth(year, hebrewMonth(year, month))
+}
+
+func (hebrewCalendar) hasLeapMonth(year, month int) bool {
+	return month == 5 && hebrewLeapYear(year)
+}
@@ -24,6 +24,8 @@ const (
 	dateFormat        = "20060102"
 	datetimeFormat    = "20060102T150405"
 	datetimeUTCFormat = "20060102T150405Z"
+	timeFormat        = "150405"
+	timeUTCFormat     = "150405Z"
 )
 
 // Params is a set of property parameters.
@@ -120,15 +122,152 @@ func (prop *Prop) Bool() (bool, error) {
 	}
 }
 
-// DateTime parses the property value as a date-time or a date.
+// DateTime parses the property value as a date-time or a date. The TZID
+// parameter is resolved with DefaultTimezoneResolver, use DateTimeWith to
+// resolve the TZIDs defined by a VTIMEZONE of the calendar.
 func (prop *Prop) DateTime(loc *time.Location) (time.Time, error) {
 	// Default to UTC, if there is no given location.
 	if loc == nil {
 		loc = time.UTC
 	}
+	return prop.dateTime(prop.Value, loc, nil)
+}
+
+// DateTimeWith is like DateTime, but resolves the TZID parameter with
+// resolver instead of DefaultTimezoneResolver, e.g. with the Calendar
+// containing the property. A nil resolver uses DefaultTimezoneResolver.
+func (prop *Prop) DateTimeWith(loc *time.Location, resolver TimezoneResolver) (time.Time, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return prop.dateTime(prop.Value, loc, resolverLoader(resolver))
+}
+
+func (prop *Prop) dateTime(s string, loc *time.Location, load timezoneLoader) (time.Time, error) {
+	dt, err := prop.dateTimeValue(s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return dt.time(loc, load)
+}
+
+// DateTimeForm is the form of a DATE or DATE-TIME value, defined in RFC 5545
+// sections 3.3.4 and 3.3.5.
+type DateTimeForm int
+
+const (
+	// DateTimeDate is a DATE value, e.g. "19970714".
+	DateTimeDate DateTimeForm = iota + 1
+	// DateTimeFloating is a DATE-TIME value without a time zone, e.g.
+	// "19980118T230000".
+	DateTimeFloating
+	// DateTimeUTC is a DATE-TIME value in UTC, e.g. "19980119T070000Z".
+	DateTimeUTC
+	// DateTimeZoned is a DATE-TIME value with a TZID parameter, e.g.
+	// "TZID=America/New_York:19980119T020000".
+	DateTimeZoned
+)
+
+// DateTime is a DATE or DATE-TIME value. Unlike time.Time, it records the
+// form of the value, so that it can be written back unchanged.
+type DateTime struct {
+	Form DateTimeForm
+	// Wall is the wall-clock value, in UTC. Its time of day is zero for
+	// dates.
+	Wall time.Time
+	// TZID is the TZID parameter of zoned values.
+	TZID string
+}
+
+// NewDate creates a DATE value from the date of t.
+func NewDate(t time.Time) DateTime {
+	y, m, d := t.Date()
+	return DateTime{Form: DateTimeDate, Wall: time.Date(y, m, d, 0, 0, 0, 0, time.UTC)}
+}
+
+// NewFloatingDateTime creates a floating DATE-TIME value from the wall-clock
+// value of t.
+func NewFloatingDateTime(t time.Time) DateTime {
+	return DateTime{Form: DateTimeFloating, Wall: wallClock(t)}
+}
+
+// NewDateTime creates a DATE-TIME value from t. The value is in UTC if t is
+// in UTC, otherwise the TZID is the name of its location.
+func NewDateTime(t time.Time) DateTime {
+	switch t.Location() {
+	case nil, time.UTC:
+		return DateTime{Form: DateTimeUTC, Wall: t.UTC()}
+	default:
+		return DateTime{Form: DateTimeZoned, Wall: wallClock(t), TZID: t.Location().String()}
+	}
+}
+
+// wallClock returns the wall-clock value of t, in UTC.
+func wallClock(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// IsZero reports whether dt is the zero value, e.g. for an absent property.
+func (dt DateTime) IsZero() bool {
+	return dt.Form == 0
+}
+
+// IsDate reports whether dt is a DATE value.
+func (dt DateTime) IsDate() bool {
+	return dt.Form == DateTimeDate
+}
+
+// Time resolves the value to an instant. Dates and floating date-times are
+// interpreted in loc, which defaults to UTC.
+func (dt DateTime) Time(loc *time.Location) (time.Time, error) {
+	return dt.time(loc, nil)
+}
+
+func (dt DateTime) time(loc *time.Location, load timezoneLoader) (time.Time, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	switch dt.Form {
+	case DateTimeUTC:
+		return dt.Wall, nil
+	case DateTimeZoned:
+		var err error
+		loc, err = load.load(dt.TZID)
+		if err != nil {
+			return time.Time{}, err
+		}
+	case DateTimeDate, DateTimeFloating:
+		// Interpreted in loc
+	default:
+		return time.Time{}, fmt.Errorf("ical: invalid date-time form: %v", dt.Form)
+	}
 
+	return localTime(dt.Wall, loc), nil
+}
+
+// String formats the value, without its TZID parameter.
+func (dt DateTime) String() string {
+	switch dt.Form {
+	case DateTimeDate:
+		return dt.Wall.Format(dateFormat)
+	case DateTimeUTC:
+		return dt.Wall.Format(datetimeUTCFormat)
+	default:
+		return dt.Wall.Format(datetimeFormat)
+	}
+}
+
+// DateTimeValue parses the property value as a date-time or a date,
+// preserving its form.
+func (prop *Prop) DateTimeValue() (DateTime, error) {
+	return prop.dateTimeValue(prop.Value)
+}
+
+func (prop *Prop) dateTimeValue(s string) (DateTime, error) {
 	valueType := prop.ValueType()
-	valueLength := len(prop.Value)
+	valueLength := len(s)
 	if valueType == ValueDefault {
 		switch valueLength {
 		case len(dateFormat):
@@ -138,25 +277,171 @@ func (prop *Prop) DateTime(loc *time.Location) (time.Time, error) {
 		}
 	}
 
+	var (
+		dt  DateTime
+		err error
+	)
 	switch valueType {
 	case ValueDate:
-		return time.ParseInLocation(dateFormat, prop.Value, loc)
+		dt.Form = DateTimeDate
+		dt.Wall, err = time.ParseInLocation(dateFormat, s, time.UTC)
 	case ValueDateTime:
 		if valueLength == len(datetimeUTCFormat) {
-			return time.ParseInLocation(datetimeUTCFormat, prop.Value, time.UTC)
+			dt.Form = DateTimeUTC
+			dt.Wall, err = time.ParseInLocation(datetimeUTCFormat, s, time.UTC)
+			break
 		}
-		// Use the TZID location, if available.
-		if tzid := prop.Params.Get(PropTimezoneID); tzid != "" {
-			tzLoc, err := time.LoadLocation(tzid)
-			if err != nil {
-				return time.Time{}, err
-			}
-			loc = tzLoc
+		if dt.TZID = prop.Params.Get(PropTimezoneID); dt.TZID != "" {
+			dt.Form = DateTimeZoned
+		} else {
+			dt.Form = DateTimeFloating
+		}
+		dt.Wall, err = time.ParseInLocation(datetimeFormat, s, time.UTC)
+	default:
+		return DateTime{}, fmt.Errorf("ical: cannot process: (%q) %s", valueType, s)
+	}
+	if err != nil {
+		return DateTime{}, err
+	}
+	return dt, nil
+}
+
+// SetDateTimeValue sets the property value to a date-time or a date, in the
+// form recorded by dt.
+func (prop *Prop) SetDateTimeValue(dt DateTime) {
+	if dt.IsDate() {
+		prop.SetValueType(ValueDate)
+	} else {
+		prop.SetValueType(ValueDateTime)
+	}
+	if dt.Form == DateTimeZoned {
+		prop.Params.Set(PropTimezoneID, dt.TZID)
+	} else {
+		prop.Params.Del(PropTimezoneID)
+	}
+	prop.Value = dt.String()
+}
+
+// DateTimeList parses the property value as a comma-separated list of
+// date-times or dates. If the value type is PERIOD, the start of each period
+// is returned.
+func (prop *Prop) DateTimeList(loc *time.Location) ([]time.Time, error) {
+	return prop.dateTimeList(loc, nil)
+}
+
+// DateTimeListWith is like DateTimeList, but resolves the TZID parameter with
+// resolver, like DateTimeWith.
+func (prop *Prop) DateTimeListWith(loc *time.Location, resolver TimezoneResolver) ([]time.Time, error) {
+	return prop.dateTimeList(loc, resolverLoader(resolver))
+}
+
+func (prop *Prop) dateTimeList(loc *time.Location, load timezoneLoader) ([]time.Time, error) {
+	// Default to UTC, if there is no given location.
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	if prop.ValueType() == ValuePeriod {
+		periods, err := prop.periodList(loc, load)
+		if err != nil {
+			return nil, err
+		}
+		l := make([]time.Time, len(periods))
+		for i, period := range periods {
+			l[i] = period.Start
 		}
-		return time.ParseInLocation(datetimeFormat, prop.Value, loc)
+		return l, nil
 	}
 
-	return time.Time{}, fmt.Errorf("ical: cannot process: (%q) %s", valueType, prop.Value)
+	var l []time.Time
+	for _, s := range strings.Split(prop.Value, ",") {
+		t, err := prop.dateTime(s, loc, load)
+		if err != nil {
+			return nil, err
+		}
+		l = append(l, t)
+	}
+	return l, nil
+}
+
+// isDate checks whether the property value is a DATE.
+func (prop *Prop) isDate() bool {
+	switch prop.ValueType() {
+	case ValueDate:
+		return true
+	case ValueDefault:
+		return len(prop.Value) == len(dateFormat)
+	default:
+		return false
+	}
+}
+
+// location returns the location described by the TZID parameter, or loc if
+// the parameter is absent.
+func (prop *Prop) location(loc *time.Location, load timezoneLoader) (*time.Location, error) {
+	// Use the TZID location, if available.
+	if tzid := prop.Params.Get(PropTimezoneID); tzid != "" {
+		return load.load(tzid)
+	}
+	return loc, nil
+}
+
+// timezoneLoader resolves a TZID to a location. A nil loader uses
+// DefaultTimezoneResolver.
+type timezoneLoader func(tzid string) (*time.Location, error)
+
+func (load timezoneLoader) load(tzid string) (*time.Location, error) {
+	if load != nil {
+		return load(tzid)
+	}
+	return DefaultTimezoneResolver.LoadLocation(tzid)
+}
+
+// resolverLoader returns the loader for a resolver. A nil resolver uses
+// DefaultTimezoneResolver.
+func resolverLoader(resolver TimezoneResolver) timezoneLoader {
+	if resolver == nil {
+		return nil
+	}
+	return resolver.LoadLocation
+}
+
+// cached returns a loader resolving each TZID only once.
+func (load timezoneLoader) cached() timezoneLoader {
+	cache := make(map[string]*time.Location)
+	return func(tzid string) (*time.Location, error) {
+		if loc, ok := cache[tzid]; ok {
+			return loc, nil
+		}
+		loc, err := load.load(tzid)
+		if err == nil {
+			cache[tzid] = loc
+		}
+		return loc, err
+	}
+}
+
+// parseDateTime parses a DATE-TIME value. Values in UTC form ignore loc.
+func parseDateTime(s string, loc *time.Location) (time.Time, error) {
+	if len(s) == len(datetimeUTCFormat) {
+		return time.ParseInLocation(datetimeUTCFormat, s, time.UTC)
+	}
+	wall, err := time.ParseInLocation(datetimeFormat, s, time.UTC)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return localTime(wall, loc), nil
+}
+
+// formatDateTime formats a DATE-TIME value. The UTC form is used if t is in
+// UTC.
+func formatDateTime(t time.Time) string {
+	switch t.Location() {
+	case nil, time.UTC:
+		return t.Format(datetimeUTCFormat)
+	default:
+		return t.Format(datetimeFormat)
+	}
 }
 
 func (prop *Prop) SetDate(t time.Time) {
@@ -166,13 +451,135 @@ func (prop *Prop) SetDate(t time.Time) {
 
 func (prop *Prop) SetDateTime(t time.Time) {
 	prop.SetValueType(ValueDateTime)
-	switch t.Location() {
+	prop.setTimezoneID(t.Location())
+	prop.Value = formatDateTime(t)
+}
+
+// SetDateList sets the property value to a list of dates.
+func (prop *Prop) SetDateList(l []time.Time) {
+	prop.SetValueType(ValueDate)
+	values := make([]string, len(l))
+	for i, t := range l {
+		values[i] = t.Format(dateFormat)
+	}
+	prop.Value = strings.Join(values, ",")
+}
+
+// SetDateTimeList sets the property value to a list of date-times. The TZID
+// parameter is derived from the location of the first date-time.
+func (prop *Prop) SetDateTimeList(l []time.Time) {
+	prop.SetValueType(ValueDateTime)
+	prop.Params.Del(PropTimezoneID)
+	values := make([]string, len(l))
+	for i, t := range l {
+		if i == 0 {
+			prop.setTimezoneID(t.Location())
+		} else {
+			t = t.In(l[0].Location())
+		}
+		values[i] = formatDateTime(t)
+	}
+	prop.Value = strings.Join(values, ",")
+}
+
+// setTimezoneID sets the TZID parameter to the name of loc, unless loc is
+// UTC.
+func (prop *Prop) setTimezoneID(loc *time.Location) {
+	switch loc {
 	case nil, time.UTC:
-		prop.Value = t.Format(datetimeUTCFormat)
+		// UTC values carry their own "Z" suffix
 	default:
-		prop.Params.Set(PropTimezoneID, t.Location().String())
-		prop.Value = t.Format(datetimeFormat)
+		prop.Params.Set(PropTimezoneID, loc.String())
+	}
+}
+
+// Duration is a duration of time, defined in RFC 5545 section 3.3.6.
+//
+// Days are nominal: adding a day keeps the same wall-clock time, even across
+// a daylight saving time transition. Weeks are represented as 7 days. Time is
+// the exact part of the duration. Days and Time should have the same sign,
+// since RFC 5545 can't express a duration with parts of different signs.
+type Duration struct {
+	Days int
+	Time time.Duration
+}
+
+// AddTo returns the time t+dur. Days are added using calendar arithmetic in
+// the location of t.
+func (dur Duration) AddTo(t time.Time) time.Time {
+	return t.AddDate(0, 0, dur.Days).Add(dur.Time)
+}
+
+// normalize returns an equivalent duration whose parts have the same sign.
+// Days are converted to exact 24-hour periods if the signs differ.
+func (dur Duration) normalize() Duration {
+	if (dur.Days > 0 && dur.Time < 0) || (dur.Days < 0 && dur.Time > 0) {
+		return Duration{Time: dur.Time + time.Duration(dur.Days)*24*time.Hour}
+	}
+	return dur
+}
+
+// check returns an error if the duration can't be represented in RFC 5545.
+func (dur Duration) check() error {
+	if dur.Time%time.Second != 0 {
+		return fmt.Errorf("ical: duration %v has a sub-second part", dur.Time)
+	}
+	return nil
+}
+
+// String formats the duration in its most compact RFC 5545 form. If Days and
+// Time have different signs, days are formatted as exact 24-hour periods.
+// Sub-second parts are truncated, use Prop.SetDuration to reject them.
+func (dur Duration) String() string {
+	dur = dur.normalize()
+	neg := dur.Days < 0 || dur.Time < 0
+	days := dur.Days
+	if days < 0 {
+		days = -days
+	}
+	sec := int64(dur.Time / time.Second)
+	if sec < 0 {
+		sec = -sec
+	}
+
+	var sb strings.Builder
+	if neg {
+		sb.WriteByte('-')
 	}
+	sb.WriteByte('P')
+
+	if days == 0 && sec == 0 {
+		sb.WriteString("T0S")
+		return sb.String()
+	}
+
+	if sec == 0 && days%7 == 0 {
+		sb.WriteString(strconv.Itoa(days / 7))
+		sb.WriteByte('W')
+		return sb.String()
+	}
+	if days > 0 {
+		sb.WriteString(strconv.Itoa(days))
+		sb.WriteByte('D')
+	}
+
+	if sec > 0 {
+		sb.WriteByte('T')
+		if h := sec / 3600; h > 0 {
+			sb.WriteString(strconv.FormatInt(h, 10))
+			sb.WriteByte('H')
+		}
+		if m := sec / 60 % 60; m > 0 {
+			sb.WriteString(strconv.FormatInt(m, 10))
+			sb.WriteByte('M')
+		}
+		if s := sec % 60; s > 0 {
+			sb.WriteString(strconv.FormatInt(s, 10))
+			sb.WriteByte('S')
+		}
+	}
+
+	return sb.String()
 }
 
 type durationParser struct {
@@ -187,7 +594,7 @@ func (p *durationParser) consume(c byte) bool {
 	return true
 }
 
-func (p *durationParser) parseCount() (time.Duration, error) {
+func (p *durationParser) parseCount() (int64, error) {
 	// Find the first non-digit
 	i := strings.IndexFunc(p.s, func(r rune) bool {
 		return r < '0' || r > '9'
@@ -199,25 +606,25 @@ func (p *durationParser) parseCount() (time.Duration, error) {
 		i = len(p.s)
 	}
 
-	n, err := strconv.ParseUint(p.s[:i], 10, 64)
+	n, err := strconv.ParseInt(p.s[:i], 10, 32)
 	if err != nil {
 		return 0, fmt.Errorf("ical: invalid duration: %v", err)
 	}
 	p.s = p.s[i:]
-	return time.Duration(n), nil
+	return n, nil
 }
 
-func (p *durationParser) parseDuration() (time.Duration, error) {
+func (p *durationParser) parseDuration() (Duration, error) {
 	neg := p.consume('-')
 	if !neg {
 		_ = p.consume('+')
 	}
 
 	if !p.consume('P') {
-		return 0, fmt.Errorf("ical: invalid duration: expected 'P'")
+		return Duration{}, fmt.Errorf("ical: invalid duration: expected 'P'")
 	}
 
-	var dur time.Duration
+	var dur Duration
 	isTime := false
 	for len(p.s) > 0 {
 		if p.consume('T') {
@@ -226,62 +633,249 @@ func (p *durationParser) parseDuration() (time.Duration, error) {
 
 		n, err := p.parseCount()
 		if err != nil {
-			return 0, err
+			return Duration{}, err
 		}
 
 		if !isTime {
 			if p.consume('D') {
-				dur += n * 24 * time.Hour
+				dur.Days += int(n)
 			} else if p.consume('W') {
-				dur += n * 7 * 24 * time.Hour
+				dur.Days += int(n) * 7
 			} else {
-				return 0, fmt.Errorf("ical: invalid duration: expected 'D' or 'W'")
+				return Duration{}, fmt.Errorf("ical: invalid duration: expected 'D' or 'W'")
 			}
 		} else {
 			if p.consume('H') {
-				dur += n * time.Hour
+				dur.Time += time.Duration(n) * time.Hour
 			} else if p.consume('M') {
-				dur += n * time.Minute
+				dur.Time += time.Duration(n) * time.Minute
 			} else if p.consume('S') {
-				dur += n * time.Second
+				dur.Time += time.Duration(n) * time.Second
 			} else {
-				return 0, fmt.Errorf("ical: invalid duration: expected 'H', 'M' or 'S'")
+				return Duration{}, fmt.Errorf("ical: invalid duration: expected 'H', 'M' or 'S'")
 			}
 		}
 	}
 
 	if neg {
-		dur = -dur
+		dur.Days = -dur.Days
+		dur.Time = -dur.Time
 	}
 	return dur, nil
 }
 
-func (prop *Prop) Duration() (time.Duration, error) {
+func (prop *Prop) Duration() (Duration, error) {
 	if err := prop.expectValueType(ValueDuration); err != nil {
-		return 0, err
+		return Duration{}, err
 	}
 	p := durationParser{strings.ToUpper(prop.Value)}
 	return p.parseDuration()
 }
 
-func (prop *Prop) SetDuration(dur time.Duration) {
+// SetDuration sets the property value to a duration. An error is returned if
+// the duration has a sub-second part.
+func (prop *Prop) SetDuration(dur Duration) error {
+	if err := dur.check(); err != nil {
+		return err
+	}
 	prop.SetValueType(ValueDuration)
+	prop.Value = dur.String()
+	return nil
+}
 
-	sec := dur.Milliseconds() / 1000
-	neg := sec < 0
-	if sec < 0 {
-		sec = -sec
+// Period is a precise period of time, defined in RFC 5545 section 3.3.9.
+//
+// A period is either explicit, in which case End is set, or has a start and a
+// positive duration, in which case Duration is set.
+type Period struct {
+	Start    time.Time
+	End      time.Time
+	Duration Duration
+}
+
+func parsePeriod(s string, loc *time.Location) (Period, error) {
+	i := strings.IndexByte(s, '/')
+	if i < 0 {
+		return Period{}, fmt.Errorf("ical: invalid period: missing slash")
 	}
 
-	var s string
-	if neg {
-		s += "-"
+	start, err := parseDateTime(s[:i], loc)
+	if err != nil {
+		return Period{}, fmt.Errorf("ical: invalid period start: %v", err)
 	}
-	s += "PT"
-	s += strconv.FormatInt(sec, 10)
-	s += "S"
 
-	prop.Value = s
+	period := Period{Start: start}
+	if v := strings.ToUpper(s[i+1:]); strings.HasPrefix(v, "P") || strings.HasPrefix(v, "+P") {
+		p := durationParser{v}
+		period.Duration, err = p.parseDuration()
+	} else {
+		period.End, err = parseDateTime(v, loc)
+	}
+	if err != nil {
+		return Period{}, fmt.Errorf("ical: invalid period end: %v", err)
+	}
+
+	return period, nil
+}
+
+func formatPeriod(period Period) (string, error) {
+	s := formatDateTime(period.Start) + "/"
+	if period.End.IsZero() {
+		if err := period.Duration.check(); err != nil {
+			return "", err
+		}
+		s += period.Duration.String()
+	} else {
+		s += formatDateTime(period.End.In(period.Start.Location()))
+	}
+	return s, nil
+}
+
+// PeriodList parses the property value as a comma-separated list of periods.
+// Periods which aren't in UTC are parsed in the location specified by the
+// TZID parameter, falling back to loc.
+func (prop *Prop) PeriodList(loc *time.Location) ([]Period, error) {
+	return prop.periodList(loc, nil)
+}
+
+func (prop *Prop) periodList(loc *time.Location, load timezoneLoader) ([]Period, error) {
+	if err := prop.expectValueType(ValuePeriod); err != nil {
+		return nil, err
+	}
+
+	// Default to UTC, if there is no given location.
+	if loc == nil {
+		loc = time.UTC
+	}
+	loc, err := prop.location(loc, load)
+	if err != nil {
+		return nil, err
+	}
+
+	var l []Period
+	for _, s := range strings.Split(prop.Value, ",") {
+		period, err := parsePeriod(s, loc)
+		if err != nil {
+			return nil, err
+		}
+		l = append(l, period)
+	}
+	return l, nil
+}
+
+// SetPeriodList sets the property value to a list of periods. The TZID
+// parameter is derived from the location of the first period start. An error
+// is returned if a duration has a sub-second part.
+func (prop *Prop) SetPeriodList(l []Period) error {
+	var sb strings.Builder
+	for i, period := range l {
+		if i > 0 {
+			sb.WriteByte(',')
+			period.Start = period.Start.In(l[0].Start.Location())
+		}
+		s, err := formatPeriod(period)
+		if err != nil {
+			return err
+		}
+		sb.WriteString(s)
+	}
+
+	prop.SetValueType(ValuePeriod)
+	prop.Params.Del(PropTimezoneID)
+	if len(l) > 0 {
+		prop.setTimezoneID(l[0].Start.Location())
+	}
+	prop.Value = sb.String()
+	return nil
+}
+
+// Period parses the property value as a single period.
+func (prop *Prop) Period(loc *time.Location) (Period, error) {
+	l, err := prop.PeriodList(loc)
+	if err != nil {
+		return Period{}, err
+	}
+	return l[0], nil
+}
+
+func (prop *Prop) SetPeriod(period Period) error {
+	return prop.SetPeriodList([]Period{period})
+}
+
+// Time is a time of the day, defined in RFC 5545 section 3.3.12.
+type Time struct {
+	Hour, Minute, Second int
+	// Location is nil for floating times, time.UTC for times in UTC form, and
+	// the location specified by the TZID parameter otherwise.
+	Location *time.Location
+}
+
+// On returns the time.Time for this time of the day on the given date.
+// Floating times are interpreted in loc.
+func (t Time) On(year int, month time.Month, day int, loc *time.Location) time.Time {
+	if t.Location != nil {
+		loc = t.Location
+	} else if loc == nil {
+		loc = time.UTC
+	}
+	return localTime(time.Date(year, month, day, t.Hour, t.Minute, t.Second, 0, time.UTC), loc)
+}
+
+// Time parses the property value as a time of the day.
+func (prop *Prop) Time() (Time, error) {
+	if err := prop.expectValueType(ValueTime); err != nil {
+		return Time{}, err
+	}
+
+	s := prop.Value
+	var loc *time.Location
+	switch len(s) {
+	case len(timeUTCFormat):
+		if s[len(s)-1] != 'Z' {
+			return Time{}, fmt.Errorf("ical: invalid time: %q", prop.Value)
+		}
+		s = s[:len(s)-1]
+		loc = time.UTC
+	case len(timeFormat):
+		var err error
+		if loc, err = prop.location(nil, nil); err != nil {
+			return Time{}, err
+		}
+	default:
+		return Time{}, fmt.Errorf("ical: invalid time: %q", prop.Value)
+	}
+
+	var fields [3]int
+	for i := range fields {
+		n, err := strconv.ParseUint(s[2*i:2*i+2], 10, 8)
+		if err != nil {
+			return Time{}, fmt.Errorf("ical: invalid time: %v", err)
+		}
+		fields[i] = int(n)
+	}
+	// A second value of 60 denotes a positive leap second
+	if fields[0] > 23 || fields[1] > 59 || fields[2] > 60 {
+		return Time{}, fmt.Errorf("ical: invalid time: %q out of range", prop.Value)
+	}
+
+	return Time{
+		Hour:     fields[0],
+		Minute:   fields[1],
+		Second:   fields[2],
+		Location: loc,
+	}, nil
+}
+
+func (prop *Prop) SetTime(t Time) {
+	prop.SetValueType(ValueTime)
+	prop.Params.Del(PropTimezoneID)
+	prop.Value = fmt.Sprintf("%02d%02d%02d", t.Hour, t.Minute, t.Second)
+	if t.Location != nil {
+		prop.setTimezoneID(t.Location)
+		if t.Location == time.UTC {
+			prop.Value += "Z"
+		}
+	}
 }
 
 func (prop *Prop) Float() (float64, error) {
@@ -291,6 +885,60 @@ func (prop *Prop) Float() (float64, error) {
 	return strconv.ParseFloat(prop.Value, 64)
 }
 
+// Geo is a global position, defined in RFC 5545 section 3.8.1.6.
+type Geo struct {
+	Latitude, Longitude float64
+}
+
+// Geo parses the property value as a latitude and a longitude, separated by a
+// semicolon.
+func (prop *Prop) Geo() (*Geo, error) {
+	if err := prop.expectValueType(ValueFloat); err != nil {
+		return nil, err
+	}
+
+	i := strings.IndexByte(prop.Value, ';')
+	if i < 0 {
+		return nil, fmt.Errorf("ical: invalid geo: missing semicolon")
+	}
+	lat, err := strconv.ParseFloat(prop.Value[:i], 64)
+	if err != nil {
+		return nil, fmt.Errorf("ical: invalid geo latitude: %v", err)
+	}
+	lon, err := strconv.ParseFloat(prop.Value[i+1:], 64)
+	if err != nil {
+		return nil, fmt.Errorf("ical: invalid geo longitude: %v", err)
+	}
+
+	if err := checkGeo(lat, lon); err != nil {
+		return nil, err
+	}
+	return &Geo{Latitude: lat, Longitude: lon}, nil
+}
+
+// checkGeo returns an error if the latitude isn't in [-90, 90] or the
+// longitude isn't in [-180, 180].
+func checkGeo(lat, lon float64) error {
+	if !(lat >= -90 && lat <= 90) {
+		return fmt.Errorf("ical: invalid geo latitude: %v out of range", lat)
+	}
+	if !(lon >= -180 && lon <= 180) {
+		return fmt.Errorf("ical: invalid geo longitude: %v out of range", lon)
+	}
+	return nil
+}
+
+// SetGeo sets the property value to a latitude and a longitude. An error is
+// returned if they are out of range.
+func (prop *Prop) SetGeo(lat, lon float64) error {
+	if err := checkGeo(lat, lon); err != nil {
+		return err
+	}
+	prop.SetValueType(ValueFloat)
+	prop.Value = strconv.FormatFloat(lat, 'f', -1, 64) + ";" + strconv.FormatFloat(lon, 'f', -1, 64)
+	return nil
+}
+
 func (prop *Prop) Int() (int, error) {
 	if err := prop.expectValueType(ValueInt); err != nil {
 		return 0, err
@@ -302,17 +950,22 @@ func (prop *Prop) TextList() ([]string, error) {
 	if err := prop.expectValueType(ValueText); err != nil {
 		return nil, err
 	}
+	return splitText(prop.Value, ',')
+}
 
+// splitText splits escaped text on each unescaped occurrence of sep, and
+// unescapes the resulting items.
+func splitText(s string, sep byte) ([]string, error) {
 	var l []string
 	var sb strings.Builder
-	for i := 0; i < len(prop.Value); i++ {
-		switch c := prop.Value[i]; c {
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
 		case '\\':
 			i++
-			if i >= len(prop.Value) {
+			if i >= len(s) {
 				return nil, fmt.Errorf("ical: malformed text: antislash at end of text")
 			}
-			switch c := prop.Value[i]; c {
+			switch c := s[i]; c {
 			case '\\', ';', ',':
 				sb.WriteByte(c)
 			case 'n', 'N':
@@ -320,7 +973,7 @@ func (prop *Prop) TextList() ([]string, error) {
 			default:
 				return nil, fmt.Errorf("ical: malformed text: invalid escape sequence '\\%v'", c)
 			}
-		case ',':
+		case sep:
 			l = append(l, sb.String())
 			sb.Reset()
 		default:
@@ -340,21 +993,24 @@ func (prop *Prop) SetTextList(l []string) {
 		if i > 0 {
 			sb.WriteByte(',')
 		}
+		writeEscapedText(&sb, text)
+	}
+	prop.Value = sb.String()
+}
 
-		sb.Grow(len(text))
-		for _, r := range text {
-			switch r {
-			case '\\', ';', ',':
-				sb.WriteByte('\\')
-				sb.WriteRune(r)
-			case '\n':
-				sb.WriteString("\\n")
-			default:
-				sb.WriteRune(r)
-			}
+func writeEscapedText(sb *strings.Builder, text string) {
+	sb.Grow(len(text))
+	for _, r := range text {
+		switch r {
+		case '\\', ';', ',':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case '\n':
+			sb.WriteString("\\n")
+		default:
+			sb.WriteRune(r)
 		}
 	}
-	prop.Value = sb.String()
 }
 
 func (prop *Prop) Text() (string, error) {
@@ -394,7 +1050,78 @@ func (prop *Prop) SetURI(u *url.URL) {
 	prop.Value = u.String()
 }
 
-// TODO: Period, Time, UTCOffset
+// CalendarAddress parses the property value as a calendar user address, e.g.
+// a "mailto:" URI.
+func (prop *Prop) CalendarAddress() (*url.URL, error) {
+	if err := prop.expectValueType(ValueCalendarAddress); err != nil {
+		return nil, err
+	}
+	return url.Parse(prop.Value)
+}
+
+func (prop *Prop) SetCalendarAddress(u *url.URL) {
+	prop.SetValueType(ValueCalendarAddress)
+	prop.Value = u.String()
+}
+
+// UTCOffset parses the property value as an offset from UTC.
+func (prop *Prop) UTCOffset() (time.Duration, error) {
+	if err := prop.expectValueType(ValueUTCOffset); err != nil {
+		return 0, err
+	}
+
+	s := prop.Value
+	if len(s) != len("+HHMM") && len(s) != len("+HHMMSS") {
+		return 0, fmt.Errorf("ical: invalid UTC offset: %q", prop.Value)
+	}
+
+	var neg bool
+	switch s[0] {
+	case '+':
+	case '-':
+		neg = true
+	default:
+		return 0, fmt.Errorf("ical: invalid UTC offset: expected '+' or '-'")
+	}
+
+	var offset time.Duration
+	units := []time.Duration{time.Hour, time.Minute, time.Second}
+	for i := 1; i < len(s); i += 2 {
+		n, err := strconv.ParseUint(s[i:i+2], 10, 8)
+		if err != nil {
+			return 0, fmt.Errorf("ical: invalid UTC offset: %v", err)
+		}
+		if (i == 1 && n > 23) || n > 59 {
+			return 0, fmt.Errorf("ical: invalid UTC offset: %q out of range", prop.Value)
+		}
+		offset += time.Duration(n) * units[i/2]
+	}
+
+	if neg {
+		if offset == 0 {
+			return 0, fmt.Errorf("ical: invalid UTC offset: negative zero")
+		}
+		offset = -offset
+	}
+	return offset, nil
+}
+
+func (prop *Prop) SetUTCOffset(offset time.Duration) {
+	prop.SetValueType(ValueUTCOffset)
+
+	sign := '+'
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+
+	sec := int64(offset / time.Second)
+	s := fmt.Sprintf("%c%02d%02d", sign, sec/3600, sec/60%60)
+	if sec%60 != 0 {
+		s += fmt.Sprintf("%02d", sec%60)
+	}
+	prop.Value = s
+}
 
 // Props is a set of component properties.
 type Props map[string][]Prop
@@ -442,6 +1169,21 @@ func (props Props) DateTime(name string, loc *time.Location) (time.Time, error)
 	return time.Time{}, nil
 }
 
+// DateTimeValue returns the parsed date-time or date property, or the zero
+// value if it's absent.
+func (props Props) DateTimeValue(name string) (DateTime, error) {
+	if prop := props.Get(name); prop != nil {
+		return prop.DateTimeValue()
+	}
+	return DateTime{}, nil
+}
+
+func (props Props) SetDateTimeValue(name string, dt DateTime) {
+	prop := NewProp(name)
+	prop.SetDateTimeValue(dt)
+	props.Set(prop)
+}
+
 func (props Props) SetDate(name string, t time.Time) {
 	prop := NewProp(name)
 	prop.SetDate(t)
@@ -454,6 +1196,36 @@ func (props Props) SetDateTime(name string, t time.Time) {
 	props.Set(prop)
 }
 
+// Geo returns the GEO property, or nil if it's absent.
+func (props Props) Geo() (*Geo, error) {
+	if prop := props.Get(PropGeo); prop != nil {
+		return prop.Geo()
+	}
+	return nil, nil
+}
+
+func (props Props) SetGeo(lat, lon float64) error {
+	prop := NewProp(PropGeo)
+	if err := prop.SetGeo(lat, lon); err != nil {
+		return err
+	}
+	props.Set(prop)
+	return nil
+}
+
+func (props Props) UTCOffset(name string) (time.Duration, error) {
+	if prop := props.Get(name); prop != nil {
+		return prop.UTCOffset()
+	}
+	return 0, nil
+}
+
+func (props Props) SetUTCOffset(name string, offset time.Duration) {
+	prop := NewProp(name)
+	prop.SetUTCOffset(offset)
+	props.Set(prop)
+}
+
 func (props Props) SetURI(name string, u *url.URL) {
 	prop := NewProp(name)
 	prop.SetURI(u)
@@ -498,12 +1270,12 @@ func (props Props) RecurrenceRule() (*rrule.ROption, error) {
 		return nil, err
 	}
 
-	roption, err := rrule.StrToROption(prop.Value)
+	r, err := ParseRecur(prop.Value)
 	if err != nil {
 		return nil, fmt.Errorf("ical: error parsing rrule: %v", err)
 	}
 
-	return roption, nil
+	return r.ROption(time.UTC)
 }
 
 func (props Props) SetRecurrenceRule(rule *rrule.ROption) {
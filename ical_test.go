@@ -333,6 +333,132 @@ func TestSetDateTime(t *testing.T) {
 	}
 }
 
+func TestDateTimeValue(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name   string
+		tzid   string
+		value  string
+		form   DateTimeForm
+		viewer *time.Location
+		want   time.Time
+	}{
+		{
+			name:   "date",
+			value:  "19970714",
+			form:   DateTimeDate,
+			viewer: paris,
+			want:   time.Date(1997, 7, 14, 0, 0, 0, 0, paris),
+		},
+		{
+			name:   "floating",
+			value:  "19980118T230000",
+			form:   DateTimeFloating,
+			viewer: paris,
+			want:   time.Date(1998, 1, 18, 23, 0, 0, 0, paris),
+		},
+		{
+			name:   "utc",
+			value:  "19980119T070000Z",
+			form:   DateTimeUTC,
+			viewer: paris,
+			want:   time.Date(1998, 1, 19, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "zoned",
+			tzid:   "America/New_York",
+			value:  "19980119T020000",
+			form:   DateTimeZoned,
+			viewer: paris,
+			want:   time.Date(1998, 1, 19, 2, 0, 0, 0, newYork),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prop := NewProp(PropDateTimeStart)
+			prop.Value = tc.value
+			if tc.tzid != "" {
+				prop.Params.Set(PropTimezoneID, tc.tzid)
+			}
+			if tc.form == DateTimeDate {
+				prop.SetValueType(ValueDate)
+			}
+
+			dt, err := prop.DateTimeValue()
+			if err != nil {
+				t.Fatalf("DateTimeValue() = %v", err)
+			}
+			if dt.Form != tc.form || dt.TZID != tc.tzid {
+				t.Errorf("DateTimeValue() = %#v, want form %v and TZID %q", dt, tc.form, tc.tzid)
+			}
+			if got, err := dt.Time(tc.viewer); err != nil {
+				t.Errorf("DateTime.Time() = %v", err)
+			} else if !got.Equal(tc.want) || got.Location().String() != tc.want.Location().String() {
+				t.Errorf("DateTime.Time() = %v, want %v", got, tc.want)
+			}
+
+			got := NewProp(PropDateTimeStart)
+			got.SetDateTimeValue(dt)
+			if !reflect.DeepEqual(got, prop) {
+				t.Errorf("SetDateTimeValue() = %#v, want %#v", got, prop)
+			}
+		})
+	}
+}
+
+func TestDateTimeValueUnknownTimezone(t *testing.T) {
+	prop := NewProp(PropDateTimeStart)
+	prop.Params.Set(PropTimezoneID, "/example.org/Custom")
+	prop.Value = "20240101T090000"
+
+	dt, err := prop.DateTimeValue()
+	if err != nil {
+		t.Fatalf("DateTimeValue() = %v", err)
+	}
+	if _, err := dt.Time(nil); err == nil {
+		t.Errorf("DateTime.Time() = nil, want an error")
+	}
+
+	props := make(Props)
+	props.SetDateTimeValue(PropDateTimeStart, dt)
+	if got := props.Get(PropDateTimeStart); !reflect.DeepEqual(got, prop) {
+		t.Errorf("Props.SetDateTimeValue() = %#v, want %#v", got, prop)
+	}
+}
+
+func TestNewDateTime(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+	instant := time.Date(2024, 3, 1, 9, 30, 0, 0, paris)
+
+	for _, tc := range []struct {
+		dt   DateTime
+		want string
+	}{
+		{NewDate(instant), "20240301"},
+		{NewFloatingDateTime(instant), "20240301T093000"},
+		{NewDateTime(instant), "20240301T093000"},
+		{NewDateTime(instant.UTC()), "20240301T083000Z"},
+	} {
+		if got := tc.dt.String(); got != tc.want {
+			t.Errorf("DateTime.String() = %q, want %q", got, tc.want)
+		}
+	}
+	if dt := NewDateTime(instant); dt.Form != DateTimeZoned || dt.TZID != "Europe/Paris" {
+		t.Errorf("NewDateTime() = %#v, want a zoned value", dt)
+	}
+}
+
 func TestRoundtripURI(t *testing.T) {
 	testCases := []struct {
 		Alias    string
@@ -428,3 +554,394 @@ func TestRecurrenceRuleRoundTrip(t *testing.T) {
 		t.Errorf("Props.RecurrenceRule() = %v, want %v", roption, recurrenceRule)
 	}
 }
+
+func TestPeriod(t *testing.T) {
+	localTimezone, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		Alias    string
+		Value    string
+		TZID     string
+		Expected []Period
+	}{
+		{
+			Alias: "explicit-utc",
+			Value: "19970101T180000Z/19970102T070000Z",
+			Expected: []Period{{
+				Start: time.Date(1997, time.January, 1, 18, 0, 0, 0, time.UTC),
+				End:   time.Date(1997, time.January, 2, 7, 0, 0, 0, time.UTC),
+			}},
+		},
+		{
+			Alias: "start-duration-tzid",
+			Value: "19970101T180000/PT5H30M",
+			TZID:  "Europe/Paris",
+			Expected: []Period{{
+				Start:    time.Date(1997, time.January, 1, 18, 0, 0, 0, localTimezone),
+				Duration: Duration{Time: 5*time.Hour + 30*time.Minute},
+			}},
+		},
+		{
+			Alias: "list",
+			Value: "19970308T160000Z/PT8H30M,19970308T230000Z/19970309T000000Z",
+			Expected: []Period{
+				{
+					Start:    time.Date(1997, time.March, 8, 16, 0, 0, 0, time.UTC),
+					Duration: Duration{Time: 8*time.Hour + 30*time.Minute},
+				},
+				{
+					Start: time.Date(1997, time.March, 8, 23, 0, 0, 0, time.UTC),
+					End:   time.Date(1997, time.March, 9, 0, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+	}
+
+	for _, tCase := range testCases {
+		t.Run(tCase.Alias, func(t *testing.T) {
+			p := NewProp(PropFreeBusy)
+			p.Value = tCase.Value
+			if tCase.TZID != "" {
+				p.Params.Set(PropTimezoneID, tCase.TZID)
+			}
+			l, err := p.PeriodList(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(l, tCase.Expected) {
+				t.Errorf("bad periods: %v, expected: %v", l, tCase.Expected)
+			}
+
+			p = NewProp(PropFreeBusy)
+			if err := p.SetPeriodList(tCase.Expected); err != nil {
+				t.Fatal(err)
+			}
+			if got, want := p.Params.Get(PropTimezoneID), tCase.TZID; got != want {
+				t.Errorf("bad tzid: %s, expected: %s", got, want)
+			}
+			l, err = p.PeriodList(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(l, tCase.Expected) {
+				t.Errorf("bad round-tripped periods: %v, expected: %v", l, tCase.Expected)
+			}
+		})
+	}
+}
+
+func TestTime(t *testing.T) {
+	localTimezone, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		Alias    string
+		Value    string
+		TZID     string
+		Expected Time
+	}{
+		{
+			Alias:    "floating",
+			Value:    "230000",
+			Expected: Time{Hour: 23},
+		},
+		{
+			Alias:    "utc",
+			Value:    "070000Z",
+			Expected: Time{Hour: 7, Location: time.UTC},
+		},
+		{
+			Alias:    "tzid",
+			Value:    "083015",
+			TZID:     "America/New_York",
+			Expected: Time{Hour: 8, Minute: 30, Second: 15, Location: localTimezone},
+		},
+	}
+
+	for _, tCase := range testCases {
+		t.Run(tCase.Alias, func(t *testing.T) {
+			p := NewProp("X-TIME")
+			p.SetValueType(ValueTime)
+			p.Value = tCase.Value
+			if tCase.TZID != "" {
+				p.Params.Set(PropTimezoneID, tCase.TZID)
+			}
+			got, err := p.Time()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tCase.Expected) {
+				t.Errorf("bad time: %v, expected: %v", got, tCase.Expected)
+			}
+
+			p = NewProp("X-TIME")
+			p.SetTime(tCase.Expected)
+			if got, want := p.Value, tCase.Value; got != want {
+				t.Errorf("bad value: %s, expected: %s", got, want)
+			}
+			if got, want := p.Params.Get(PropTimezoneID), tCase.TZID; got != want {
+				t.Errorf("bad tzid: %s, expected: %s", got, want)
+			}
+		})
+	}
+
+	for _, v := range []string{"2400000", "246000", "126100", "12:00:", "1200"} {
+		p := NewProp("X-TIME")
+		p.SetValueType(ValueTime)
+		p.Value = v
+		if _, err := p.Time(); err == nil {
+			t.Errorf("Prop.Time(%q) = nil, want an error", v)
+		}
+	}
+}
+
+func TestUTCOffset(t *testing.T) {
+	testCases := []struct {
+		Value    string
+		Expected time.Duration
+	}{
+		{"+0000", 0},
+		{"-0500", -5 * time.Hour},
+		{"+0530", 5*time.Hour + 30*time.Minute},
+		{"+002015", 20*time.Minute + 15*time.Second},
+		{"-1245", -(12*time.Hour + 45*time.Minute)},
+	}
+
+	for _, tCase := range testCases {
+		t.Run(tCase.Value, func(t *testing.T) {
+			p := NewProp(PropTimezoneOffsetTo)
+			p.Value = tCase.Value
+			got, err := p.UTCOffset()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tCase.Expected {
+				t.Errorf("bad offset: %v, expected: %v", got, tCase.Expected)
+			}
+
+			p = NewProp(PropTimezoneOffsetTo)
+			p.SetUTCOffset(tCase.Expected)
+			if got, want := p.Value, tCase.Value; got != want {
+				t.Errorf("bad value: %s, expected: %s", got, want)
+			}
+		})
+	}
+
+	for _, v := range []string{"-0000", "-000000", "0100", "+01", "+2400", "+0160", "+01:00"} {
+		p := NewProp(PropTimezoneOffsetTo)
+		p.Value = v
+		if _, err := p.UTCOffset(); err == nil {
+			t.Errorf("Prop.UTCOffset(%q) = nil, want an error", v)
+		}
+	}
+}
+
+func TestDateTimeList(t *testing.T) {
+	localTimezone, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		Alias     string
+		Value     string
+		ValueType ValueType
+		TZID      string
+		Expected  []time.Time
+	}{
+		{
+			Alias:     "datetime-utc",
+			Value:     "20240101T100000Z,20240108T100000Z",
+			ValueType: ValueDateTime,
+			Expected: []time.Time{
+				time.Date(2024, time.January, 1, 10, 0, 0, 0, time.UTC),
+				time.Date(2024, time.January, 8, 10, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			Alias:     "datetime-tzid",
+			Value:     "20240101T100000,20240108T100000",
+			ValueType: ValueDateTime,
+			TZID:      "Europe/Paris",
+			Expected: []time.Time{
+				time.Date(2024, time.January, 1, 10, 0, 0, 0, localTimezone),
+				time.Date(2024, time.January, 8, 10, 0, 0, 0, localTimezone),
+			},
+		},
+		{
+			Alias:     "date",
+			Value:     "20240101,20240108",
+			ValueType: ValueDate,
+			Expected: []time.Time{
+				time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, time.January, 8, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, tCase := range testCases {
+		t.Run(tCase.Alias, func(t *testing.T) {
+			p := NewProp(PropExceptionDates)
+			p.Value = tCase.Value
+			p.SetValueType(tCase.ValueType)
+			if tCase.TZID != "" {
+				p.Params.Set(PropTimezoneID, tCase.TZID)
+			}
+			l, err := p.DateTimeList(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(l, tCase.Expected) {
+				t.Errorf("bad dates: %v, expected: %v", l, tCase.Expected)
+			}
+
+			p = NewProp(PropExceptionDates)
+			if tCase.ValueType == ValueDate {
+				p.SetDateList(tCase.Expected)
+			} else {
+				p.SetDateTimeList(tCase.Expected)
+			}
+			if got, want := p.Value, tCase.Value; got != want {
+				t.Errorf("bad value: %s, expected: %s", got, want)
+			}
+			if got, want := p.Params.Get(PropTimezoneID), tCase.TZID; got != want {
+				t.Errorf("bad tzid: %s, expected: %s", got, want)
+			}
+		})
+	}
+}
+
+func TestDuration(t *testing.T) {
+	testCases := []struct {
+		Value    string
+		Expected Duration
+		Format   string
+	}{
+		{"P1D", Duration{Days: 1}, "P1D"},
+		{"P1W", Duration{Days: 7}, "P1W"},
+		{"P7D", Duration{Days: 7}, "P1W"},
+		{"PT24H", Duration{Time: 24 * time.Hour}, "PT24H"},
+		{"P15DT5H0M20S", Duration{Days: 15, Time: 5*time.Hour + 20*time.Second}, "P15DT5H20S"},
+		{"-PT15M", Duration{Time: -15 * time.Minute}, "-PT15M"},
+		{"+PT90M", Duration{Time: 90 * time.Minute}, "PT1H30M"},
+		{"PT0S", Duration{}, "PT0S"},
+	}
+
+	for _, tCase := range testCases {
+		t.Run(tCase.Value, func(t *testing.T) {
+			p := NewProp(PropDuration)
+			p.Value = tCase.Value
+			got, err := p.Duration()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tCase.Expected {
+				t.Errorf("bad duration: %#v, expected: %#v", got, tCase.Expected)
+			}
+
+			p = NewProp(PropDuration)
+			if err := p.SetDuration(tCase.Expected); err != nil {
+				t.Fatal(err)
+			}
+			if got, want := p.Value, tCase.Format; got != want {
+				t.Errorf("bad value: %s, expected: %s", got, want)
+			}
+		})
+	}
+}
+
+func TestDurationFormatMixedSigns(t *testing.T) {
+	testCases := []struct {
+		Duration Duration
+		Format   string
+	}{
+		{Duration{Days: 1, Time: -time.Hour}, "PT23H"},
+		{Duration{Days: -1, Time: time.Hour}, "-PT23H"},
+		{Duration{Days: 1, Time: -25 * time.Hour}, "-PT1H"},
+	}
+	for _, tCase := range testCases {
+		p := NewProp(PropDuration)
+		if err := p.SetDuration(tCase.Duration); err != nil {
+			t.Fatal(err)
+		}
+		if p.Value != tCase.Format {
+			t.Errorf("SetDuration(%#v) = %v, expected %v", tCase.Duration, p.Value, tCase.Format)
+		}
+	}
+
+	p := NewProp(PropDuration)
+	if err := p.SetDuration(Duration{Time: 1500 * time.Millisecond}); err == nil {
+		t.Errorf("SetDuration() = nil, expected an error for a sub-second duration")
+	}
+	if err := p.SetPeriod(Period{Start: time.Now(), Duration: Duration{Time: time.Millisecond}}); err == nil {
+		t.Errorf("SetPeriod() = nil, expected an error for a sub-second duration")
+	}
+}
+
+func TestDurationAddTo(t *testing.T) {
+	localTimezone, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Daylight saving time starts on 2024-03-31 in Europe/Paris
+	start := time.Date(2024, time.March, 30, 10, 0, 0, 0, localTimezone)
+
+	if got, want := (Duration{Days: 1}).AddTo(start), time.Date(2024, time.March, 31, 10, 0, 0, 0, localTimezone); !got.Equal(want) {
+		t.Errorf("Duration{Days: 1}.AddTo() = %v, want %v", got, want)
+	}
+	if got, want := (Duration{Time: 24 * time.Hour}).AddTo(start), time.Date(2024, time.March, 31, 11, 0, 0, 0, localTimezone); !got.Equal(want) {
+		t.Errorf("Duration{Time: 24h}.AddTo() = %v, want %v", got, want)
+	}
+}
+
+func TestGeo(t *testing.T) {
+	props := make(Props)
+	if geo, err := props.Geo(); geo != nil || err != nil {
+		t.Errorf("Props.Geo() = %v, %v, want nil, nil", geo, err)
+	}
+
+	if err := props.SetGeo(37.386013, -122.082932); err != nil {
+		t.Fatalf("Props.SetGeo() = %v", err)
+	}
+	if got, want := props.Get(PropGeo).Value, "37.386013;-122.082932"; got != want {
+		t.Errorf("bad value: %s, expected: %s", got, want)
+	}
+
+	want := &Geo{Latitude: 37.386013, Longitude: -122.082932}
+	if geo, err := props.Geo(); err != nil {
+		t.Errorf("Props.Geo() = %v", err)
+	} else if !reflect.DeepEqual(geo, want) {
+		t.Errorf("Props.Geo() = %v, want %v", geo, want)
+	}
+
+	for _, v := range []string{"90;180", "-90;-180", "0;0"} {
+		p := NewProp(PropGeo)
+		p.Value = v
+		if _, err := p.Geo(); err != nil {
+			t.Errorf("Prop.Geo(%q) = %v", v, err)
+		}
+	}
+	for _, v := range []string{"37.386013", "91;0", "-90.1;0", "0;-180.5", "0;181", "NaN;0", "a;b"} {
+		p := NewProp(PropGeo)
+		p.Value = v
+		if _, err := p.Geo(); err == nil {
+			t.Errorf("Prop.Geo(%q) = nil, want an error", v)
+		}
+	}
+
+	for _, geo := range []Geo{{91, 0}, {-90.5, 0}, {0, 180.5}, {0, -181}} {
+		props := make(Props)
+		if err := props.SetGeo(geo.Latitude, geo.Longitude); err == nil {
+			t.Errorf("Props.SetGeo(%v, %v) = nil, want an error", geo.Latitude, geo.Longitude)
+		}
+		if props.Get(PropGeo) != nil {
+			t.Errorf("Props.SetGeo(%v, %v) set an invalid value", geo.Latitude, geo.Longitude)
+		}
+	}
+}
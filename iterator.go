@@ -0,0 +1,149 @@
+package ical
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/teambition/rrule-go"
+)
+
+// IteratorOptions contains options for RecurrenceIterator.
+type IteratorOptions struct {
+	// Start and End bound the start times of the returned occurrences. Start
+	// is inclusive and End is exclusive. Zero values leave the range open.
+	Start, End time.Time
+	// After resumes an iteration: only occurrences starting strictly after
+	// After are returned, e.g. to fetch the page following an occurrence.
+	// Rules without COUNT are evaluated from the later of Start and After
+	// rather than from DTSTART.
+	After time.Time
+	// Max is the maximum number of occurrences returned. Zero means no limit.
+	Max int
+	// Resolver resolves TZIDs, e.g. the Calendar containing the component.
+	// If nil, DefaultTimezoneResolver is used.
+	Resolver TimezoneResolver
+}
+
+// RecurrenceIterator lazily iterates over the start times of the occurrences
+// of a component, in chronological order.
+type RecurrenceIterator struct {
+	next    func() (time.Time, bool)
+	options IteratorOptions
+	count   int
+}
+
+// RecurrenceIterator returns an iterator over the occurrences of the
+// component, described by its DTSTART, RRULE, RDATE and EXDATE properties.
+// Occurrences are only computed when requested, so rules without COUNT or
+// UNTIL can be iterated over. Unlike RecurrenceSet, rules evaluated in a
+// non-Gregorian calendar system aren't expanded up to a fixed horizon, and
+// all the occurrences in a DST gap or overlap are adjusted as specified in
+// RFC 5545 section 3.3.5. A component which isn't recurring has a single
+// occurrence at DTSTART.
+//
+// options may be nil.
+func (comp *Component) RecurrenceIterator(loc *time.Location, options *IteratorOptions) (*RecurrenceIterator, error) {
+	it := &RecurrenceIterator{}
+	if options != nil {
+		it.options = *options
+	}
+	load := resolverLoader(it.options.Resolver)
+
+	// Rules are evaluated from the first requested occurrence
+	from := it.options.Start
+	if it.options.After.After(from) {
+		from = it.options.After
+	}
+	next, err := instances(comp, loc, load, from)
+	if err != nil {
+		return nil, err
+	} else if next != nil {
+		it.next = next
+		return it, nil
+	}
+
+	prop := comp.Props.Get(PropDateTimeStart)
+	if prop == nil {
+		return it, nil
+	}
+	dtstart, err := prop.dateTime(prop.Value, loc, load)
+	if err != nil {
+		return nil, fmt.Errorf("ical: error parsing start time: %v", err)
+	}
+	done := false
+	it.next = func() (time.Time, bool) {
+		if done {
+			return time.Time{}, false
+		}
+		done = true
+		return dtstart, true
+	}
+	return it, nil
+}
+
+// Next returns the start time of the next occurrence, or false if there are
+// none left.
+func (it *RecurrenceIterator) Next() (time.Time, bool) {
+	if it.next == nil || (it.options.Max > 0 && it.count >= it.options.Max) {
+		return time.Time{}, false
+	}
+	for {
+		t, ok := it.next()
+		if ok && !it.options.End.IsZero() && !t.Before(it.options.End) {
+			ok = false
+		}
+		if !ok {
+			it.next = nil
+			return time.Time{}, false
+		}
+
+		if !it.options.Start.IsZero() && t.Before(it.options.Start) {
+			continue
+		}
+		if !it.options.After.IsZero() && !t.After(it.options.After) {
+			continue
+		}
+		it.count++
+		return t, true
+	}
+}
+
+// mergeOccurrences merges the occurrences of a rule with the recurrence dates
+// of a set, and removes its exception dates.
+func mergeOccurrences(rule func() (time.Time, bool), dates *rrule.Set) func() (time.Time, bool) {
+	exdates := append([]time.Time(nil), dates.GetExDate()...)
+	sort.Slice(exdates, func(i, j int) bool {
+		return exdates[i].Before(exdates[j])
+	})
+	rdates := dates.Iterator()
+
+	r, rok := rule()
+	d, dok := rdates()
+	var last time.Time
+	return func() (time.Time, bool) {
+		for rok || dok {
+			var t time.Time
+			if rok && (!dok || !d.Before(r)) {
+				t = r
+				r, rok = rule()
+			} else {
+				t = d
+				d, dok = rdates()
+			}
+
+			if !last.IsZero() && t.Equal(last) {
+				continue
+			}
+			last = t
+			for len(exdates) > 0 && exdates[0].Before(t) {
+				exdates = exdates[1:]
+			}
+			if len(exdates) > 0 && exdates[0].Equal(t) {
+				continue
+			}
+			return t, true
+		}
+		return time.Time{}, false
+	}
+}
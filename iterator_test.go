@@ -0,0 +1,211 @@
+package ical
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecurrenceIterator(t *testing.T) {
+	event := NewEvent()
+	event.Props.SetDateTime(PropDateTimeStart, time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC))
+	event.Props.Set(&Prop{Name: PropRecurrenceRule, Params: make(Params), Value: "FREQ=DAILY"})
+	event.Props.SetDateTime(PropExceptionDates, time.Date(2024, 1, 3, 9, 0, 0, 0, time.UTC))
+	event.Props.Add(&Prop{Name: PropRecurrenceDates, Params: make(Params), Value: "20240104T120000Z"})
+
+	date := func(day, hour int) time.Time {
+		return time.Date(2024, 1, day, hour, 0, 0, 0, time.UTC)
+	}
+	testCases := []struct {
+		name    string
+		options *IteratorOptions
+		want    []time.Time
+	}{
+		{
+			name:    "bounded",
+			options: &IteratorOptions{Start: date(2, 0), End: date(6, 0)},
+			want:    []time.Time{date(2, 9), date(4, 9), date(4, 12), date(5, 9)},
+		},
+		{
+			name:    "max",
+			options: &IteratorOptions{Start: date(2, 0), Max: 3},
+			want:    []time.Time{date(2, 9), date(4, 9), date(4, 12)},
+		},
+		{
+			name:    "resume",
+			options: &IteratorOptions{After: date(4, 9), Max: 3},
+			want:    []time.Time{date(4, 12), date(5, 9), date(6, 9)},
+		},
+		{
+			name:    "far future",
+			options: &IteratorOptions{Start: time.Date(2200, 1, 1, 0, 0, 0, 0, time.UTC), Max: 1},
+			want:    []time.Time{time.Date(2200, 1, 1, 9, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:    "beyond rrule-go",
+			options: &IteratorOptions{After: time.Date(2500, 1, 1, 9, 0, 0, 0, time.UTC), Max: 1},
+			want:    []time.Time{time.Date(2500, 1, 2, 9, 0, 0, 0, time.UTC)},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			it, err := event.RecurrenceIterator(nil, tc.options)
+			if err != nil {
+				t.Fatalf("Event.RecurrenceIterator() = %v", err)
+			}
+			var got []time.Time
+			for {
+				t, ok := it.Next()
+				if !ok {
+					break
+				}
+				got = append(got, t)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("RecurrenceIterator.Next() = %v, want %v", got, tc.want)
+			}
+			if _, ok := it.Next(); ok {
+				t.Errorf("RecurrenceIterator.Next() returned an occurrence after the end")
+			}
+		})
+	}
+}
+
+func TestRecurrenceIteratorSeek(t *testing.T) {
+	testCases := []struct {
+		tzid    string
+		dtstart string
+		rrule   string
+	}{
+		{"UTC", "20240101T090000", "FREQ=YEARLY;INTERVAL=3"},
+		{"UTC", "20240131T090000", "FREQ=MONTHLY;INTERVAL=5"},
+		{"UTC", "20240105T090000", "FREQ=MONTHLY;INTERVAL=2;BYDAY=-1FR;BYSETPOS=1"},
+		{"UTC", "20240103T090000", "FREQ=WEEKLY;INTERVAL=3;WKST=SU;BYDAY=SA,SU"},
+		{"UTC", "20240103T090000", "FREQ=WEEKLY;INTERVAL=2"},
+		{"UTC", "20240101T090000", "FREQ=DAILY;INTERVAL=9;UNTIL=20260101T000000Z"},
+		{"UTC", "20240101T091500", "FREQ=HOURLY;INTERVAL=7;BYHOUR=1,8,15"},
+		{"UTC", "20240101T091510", "FREQ=MINUTELY;INTERVAL=1013"},
+		{"America/New_York", "20240310T023000", "FREQ=DAILY;INTERVAL=4"},
+		{"America/New_York", "20240101T013000", "FREQ=HOURLY;INTERVAL=5"},
+		{"Europe/Paris", "20240101T023000", "FREQ=WEEKLY;BYDAY=SU"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.tzid+"/"+tc.rrule, func(t *testing.T) {
+			if _, err := time.LoadLocation(tc.tzid); err != nil {
+				t.Skipf("time zone database unavailable: %v", err)
+			}
+
+			comp := NewComponent(CompEvent)
+			comp.Props.Set(&Prop{
+				Name:   PropDateTimeStart,
+				Params: Params{PropTimezoneID: []string{tc.tzid}},
+				Value:  tc.dtstart,
+			})
+			comp.Props.Set(&Prop{Name: PropRecurrenceRule, Params: make(Params), Value: tc.rrule})
+
+			end := time.Date(2060, 1, 1, 0, 0, 0, 0, time.UTC)
+			it, err := comp.RecurrenceIterator(nil, &IteratorOptions{End: end})
+			if err != nil {
+				t.Fatalf("Component.RecurrenceIterator() = %v", err)
+			}
+			var all []time.Time
+			for t, ok := it.Next(); ok; t, ok = it.Next() {
+				all = append(all, t)
+			}
+			if len(all) < 10 {
+				t.Fatalf("RecurrenceIterator.Next() returned %v occurrences", len(all))
+			}
+
+			// Resuming after any occurrence returns the following ones
+			for i := 0; i < len(all); i += len(all)/7 + 1 {
+				it, err := comp.RecurrenceIterator(nil, &IteratorOptions{After: all[i], End: end})
+				if err != nil {
+					t.Fatalf("Component.RecurrenceIterator() = %v", err)
+				}
+				var got []time.Time
+				for t, ok := it.Next(); ok; t, ok = it.Next() {
+					got = append(got, t)
+				}
+				if want := all[i+1:]; !reflect.DeepEqual(got, want) {
+					t.Errorf("RecurrenceIterator.Next() after %v = %v, want %v", all[i], got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRecurrenceIteratorRScale(t *testing.T) {
+	comp := NewComponent(CompEvent)
+	comp.Props.SetDate(PropDateTimeStart, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC))
+	comp.Props.Set(&Prop{Name: PropRecurrenceRule, Params: make(Params), Value: "RSCALE=GREGORIAN;FREQ=YEARLY;SKIP=FORWARD"})
+	comp.Props.Set(&Prop{Name: PropExceptionDates, Params: Params{ParamValue: []string{"DATE"}}, Value: "20250301"})
+
+	// Beyond the horizon of RecurrenceSet
+	it, err := comp.RecurrenceIterator(time.UTC, &IteratorOptions{Start: time.Date(2300, 1, 1, 0, 0, 0, 0, time.UTC), Max: 2})
+	if err != nil {
+		t.Fatalf("Component.RecurrenceIterator() = %v", err)
+	}
+	var got []string
+	for {
+		t, ok := it.Next()
+		if !ok {
+			break
+		}
+		got = append(got, t.Format(dateFormat))
+	}
+	if want := []string{"23000301", "23010301"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RecurrenceIterator.Next() = %v, want %v", got, want)
+	}
+
+	it, err = comp.RecurrenceIterator(time.UTC, &IteratorOptions{Max: 3})
+	if err != nil {
+		t.Fatalf("Component.RecurrenceIterator() = %v", err)
+	}
+	got = nil
+	for {
+		t, ok := it.Next()
+		if !ok {
+			break
+		}
+		got = append(got, t.Format(dateFormat))
+	}
+	if want := []string{"20240229", "20260301", "20270301"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RecurrenceIterator.Next() = %v, want %v", got, want)
+	}
+}
+
+func TestRecurrenceIteratorCalendar(t *testing.T) {
+	cal, err := NewDecoder(strings.NewReader(customTimezoneCalendarStr)).Decode()
+	if err != nil {
+		t.Fatalf("Decode() = %v", err)
+	}
+	events := cal.Events()
+
+	it, err := events[0].RecurrenceIterator(nil, &IteratorOptions{Resolver: cal})
+	if err != nil {
+		t.Fatalf("Event.RecurrenceIterator() = %v", err)
+	}
+	n := 0
+	for {
+		if _, ok := it.Next(); !ok {
+			break
+		}
+		n++
+	}
+	if n != 4 {
+		t.Errorf("RecurrenceIterator.Next() returned %v occurrences, want 4", n)
+	}
+
+	// Events which aren't recurring have a single occurrence
+	it, err = events[1].RecurrenceIterator(nil, &IteratorOptions{Resolver: cal})
+	if err != nil {
+		t.Fatalf("Event.RecurrenceIterator() = %v", err)
+	}
+	if got, ok := it.Next(); !ok || !got.Equal(time.Date(2024, 3, 15, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("RecurrenceIterator.Next() = %v, %v, want 2024-03-15 09:00 UTC", got, ok)
+	}
+	if _, ok := it.Next(); ok {
+		t.Errorf("RecurrenceIterator.Next() returned a second occurrence")
+	}
+}
@@ -0,0 +1,102 @@
+package ical
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+
+	"golang.org/x/text/language"
+)
+
+// LocalizedText is a text value in a given language, e.g. a SUMMARY or a
+// DESCRIPTION.
+type LocalizedText struct {
+	// Language is language.Und if the LANGUAGE parameter is absent.
+	Language language.Tag
+	Text     string
+	// AltRep is an alternate text representation, e.g. an HTML document.
+	AltRep *url.URL
+}
+
+// LocalizedText parses the property as a localized text value.
+func (prop *Prop) LocalizedText() (*LocalizedText, error) {
+	text, err := prop.Text()
+	if err != nil {
+		return nil, err
+	}
+
+	lt := &LocalizedText{Language: language.Und, Text: text}
+	if v := prop.Params.Get(ParamLanguage); v != "" {
+		lt.Language, err = language.Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("ical: invalid LANGUAGE parameter: %v", err)
+		}
+	}
+	if lt.AltRep, err = parseAddressParam(prop.Params, ParamAltRep); err != nil {
+		return nil, err
+	}
+	return lt, nil
+}
+
+func (prop *Prop) SetLocalizedText(lt *LocalizedText) {
+	prop.SetText(lt.Text)
+	if lt.Language != language.Und {
+		prop.Params.Set(ParamLanguage, lt.Language.String())
+	} else {
+		prop.Params.Del(ParamLanguage)
+	}
+	setAddressParam(prop.Params, ParamAltRep, lt.AltRep)
+}
+
+// LocalizedTexts contains the variants of a text property, keyed by
+// language.
+type LocalizedTexts map[language.Tag]*LocalizedText
+
+// Match returns the variant which best matches a list of preferred
+// languages. If none matches, the variant without a language is returned,
+// if any. Nil is returned if there are no variants.
+func (texts LocalizedTexts) Match(prefs ...language.Tag) *LocalizedText {
+	if len(texts) == 0 {
+		return nil
+	}
+
+	// The first supported language is the fallback
+	tags := make([]language.Tag, 0, len(texts))
+	for tag := range texts {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if (tags[i] == language.Und) != (tags[j] == language.Und) {
+			return tags[i] == language.Und
+		}
+		return tags[i].String() < tags[j].String()
+	})
+
+	_, i, _ := language.NewMatcher(tags).Match(prefs...)
+	return texts[tags[i]]
+}
+
+// LocalizedText returns all variants of a text property, e.g. SUMMARY or
+// NAME. If several properties have the same language, the first one is
+// used.
+func (props Props) LocalizedText(name string) (LocalizedTexts, error) {
+	l := props.Values(name)
+	texts := make(LocalizedTexts, len(l))
+	for i := range l {
+		lt, err := l[i].LocalizedText()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := texts[lt.Language]; !ok {
+			texts[lt.Language] = lt
+		}
+	}
+	return texts, nil
+}
+
+// AddLocalizedText appends a variant of a text property.
+func (props Props) AddLocalizedText(name string, lt *LocalizedText) {
+	prop := NewProp(name)
+	prop.SetLocalizedText(lt)
+	props.Add(prop)
+}
@@ -0,0 +1,64 @@
+package ical
+
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestLocalizedText(t *testing.T) {
+	event := NewEvent()
+	event.Props.AddLocalizedText(PropSummary, &LocalizedText{Language: language.Und, Text: "Keynote"})
+	event.Props.AddLocalizedText(PropSummary, &LocalizedText{Language: language.French, Text: "Discours d'ouverture"})
+	event.Props.AddLocalizedText(PropSummary, &LocalizedText{
+		Language: language.German,
+		Text:     "Eröffnungsrede",
+		AltRep:   mustParseURL("http://example.org/keynote.de.html"),
+	})
+
+	if got := event.Props.Values(PropSummary)[2].Params.Get(ParamAltRep); got != "http://example.org/keynote.de.html" {
+		t.Errorf("ALTREP = %q", got)
+	}
+
+	texts, err := event.Props.LocalizedText(PropSummary)
+	if err != nil {
+		t.Fatalf("Props.LocalizedText() = %v", err)
+	}
+	if len(texts) != 3 {
+		t.Fatalf("Props.LocalizedText() returned %v variants, want 3", len(texts))
+	}
+	if got := texts[language.German].AltRep.String(); got != "http://example.org/keynote.de.html" {
+		t.Errorf("AltRep = %v", got)
+	}
+
+	testCases := []struct {
+		prefs []language.Tag
+		want  string
+	}{
+		{[]language.Tag{language.French}, "Discours d'ouverture"},
+		{[]language.Tag{language.MustParse("de-CH")}, "Eröffnungsrede"},
+		{[]language.Tag{language.Japanese, language.German}, "Eröffnungsrede"},
+		{[]language.Tag{language.Japanese}, "Keynote"},
+		{nil, "Keynote"},
+	}
+	for _, tc := range testCases {
+		if got := texts.Match(tc.prefs...); got == nil || got.Text != tc.want {
+			t.Errorf("LocalizedTexts.Match(%v) = %v, want %q", tc.prefs, got, tc.want)
+		}
+	}
+}
+
+func TestLocalizedTextInvalidLanguage(t *testing.T) {
+	prop := NewProp(PropSummary)
+	prop.SetText("Keynote")
+	prop.Params.Set(ParamLanguage, "not a language")
+	if lt, err := prop.LocalizedText(); err == nil {
+		t.Errorf("Prop.LocalizedText() = %v, want an error", lt)
+	}
+}
+
+func TestLocalizedTextsMatchEmpty(t *testing.T) {
+	if got := (LocalizedTexts{}).Match(language.English); got != nil {
+		t.Errorf("LocalizedTexts.Match() = %v, want nil", got)
+	}
+}
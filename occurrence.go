@@ -0,0 +1,351 @@
+package ical
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/teambition/rrule-go"
+)
+
+// Occurrence is an instance of an event.
+type Occurrence struct {
+	// Start and End are the inclusive start and the non-inclusive end of the
+	// occurrence.
+	Start, End time.Time
+	// RecurrenceID is the start of the instance in the recurrence set of the
+	// master component, before overrides. It is zero if the event isn't
+	// recurring.
+	RecurrenceID time.Time
+	// Component is the effective component of the occurrence: the master
+	// component, or the component overriding the instance, possibly with
+	// RANGE=THISANDFUTURE.
+	Component *Component
+}
+
+// overlaps checks whether the occurrence overlaps with the range between
+// start and end (exclusive). Occurrences without a duration overlap with the
+// range if they start during the range.
+func (occ *Occurrence) overlaps(start, end time.Time) bool {
+	if !occ.Start.Before(end) {
+		return false
+	}
+	if occ.End.After(occ.Start) {
+		return occ.End.After(start)
+	}
+	return !occ.Start.Before(start)
+}
+
+// eventGroup is a recurring event: a master component and the components
+// overriding some of its instances, identified by their RECURRENCE-ID.
+type eventGroup struct {
+	master    *Component
+	overrides []*Component
+}
+
+// Occurrences returns the occurrences of the events of the calendar
+// overlapping with the range between start and end (exclusive), sorted by
+// start time.
+//
+// Events are grouped by UID. The instances of the master component of a
+// group are replaced with the components having the same UID and a matching
+// RECURRENCE-ID. A component with RECURRENCE-ID;RANGE=THISANDFUTURE also
+// overrides the later instances: they are shifted by the difference between
+// its DTSTART and RECURRENCE-ID, and have its duration and properties.
+// Cancelled events and instances are omitted. TZIDs are
+// resolved with Calendar.LoadLocation, floating date-times and dates are
+// interpreted in the location of start.
+func (cal *Calendar) Occurrences(start, end time.Time) ([]Occurrence, error) {
+	loc := start.Location()
+	load := timezoneLoader(cal.LoadLocation).cached()
+
+	var groups []*eventGroup
+	byUID := make(map[string]*eventGroup)
+	for _, child := range cal.Children {
+		if child.Name != CompEvent {
+			continue
+		}
+
+		uid, err := child.Props.Text(PropUID)
+		if err != nil {
+			return nil, err
+		}
+		group := byUID[uid]
+		if group == nil || uid == "" {
+			group = &eventGroup{}
+			groups = append(groups, group)
+			if uid != "" {
+				byUID[uid] = group
+			}
+		}
+
+		if child.Props.Get(PropRecurrenceID) != nil {
+			group.overrides = append(group.overrides, child)
+		} else if group.master == nil {
+			group.master = child
+		} else {
+			return nil, fmt.Errorf("ical: multiple VEVENT components with UID %q", uid)
+		}
+	}
+
+	var l []Occurrence
+	for _, group := range groups {
+		occs, err := group.occurrences(start, end, loc, load)
+		if err != nil {
+			return nil, err
+		}
+		l = append(l, occs...)
+	}
+
+	sort.SliceStable(l, func(i, j int) bool {
+		return l[i].Start.Before(l[j].Start)
+	})
+	return l, nil
+}
+
+// rangeOverride is a component overriding an instance and all the later
+// ones, with RANGE=THISANDFUTURE.
+type rangeOverride struct {
+	rid time.Time
+	// occ is nil if the instances are cancelled
+	occ *Occurrence
+}
+
+func (group *eventGroup) occurrences(start, end time.Time, loc *time.Location, load timezoneLoader) ([]Occurrence, error) {
+	var l []Occurrence
+	var ranges []rangeOverride
+	overridden := make(map[int64]bool)
+	for _, comp := range group.overrides {
+		prop := comp.Props.Get(PropRecurrenceID)
+		rid, err := prop.dateTime(prop.Value, loc, load)
+		if err != nil {
+			return nil, fmt.Errorf("ical: failed to parse RECURRENCE-ID: %v", err)
+		}
+		overridden[rid.Unix()] = true
+
+		occ, err := newOccurrence(comp, rid, loc, load)
+		if err != nil {
+			return nil, err
+		} else if occ != nil && occ.overlaps(start, end) {
+			l = append(l, *occ)
+		}
+
+		if r, err := prop.Params.Range(); err != nil {
+			return nil, err
+		} else if r == RangeThisAndFuture {
+			ranges = append(ranges, rangeOverride{rid, occ})
+		}
+	}
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].rid.Before(ranges[j].rid)
+	})
+
+	if group.master == nil {
+		return l, nil
+	}
+	occ, err := newOccurrence(group.master, time.Time{}, loc, load)
+	if err != nil || occ == nil {
+		return l, err
+	}
+
+	// Instances starting before start may still overlap with the range, and
+	// overrides may shift instances in both directions
+	lookback := occ.End.Sub(occ.Start)
+	var lookahead time.Duration
+	for _, r := range ranges {
+		if r.occ == nil {
+			continue
+		}
+		shift := r.occ.Start.Sub(r.rid)
+		if d := r.occ.End.Sub(r.occ.Start) + shift; d > lookback {
+			lookback = d
+		}
+		if shift < 0 && -shift > lookahead {
+			lookahead = -shift
+		}
+	}
+	lookback += 24 * time.Hour
+	lookahead += 24 * time.Hour
+
+	next, err := instances(group.master, loc, load, start.Add(-lookback))
+	if err != nil {
+		return nil, err
+	} else if next == nil {
+		if occ.overlaps(start, end) {
+			l = append(l, *occ)
+		}
+		return l, nil
+	}
+
+	for t, ok := next(); ok && !t.After(end.Add(lookahead)); t, ok = next() {
+		if overridden[t.Unix()] {
+			continue
+		}
+
+		// The last override with RANGE=THISANDFUTURE before the instance
+		// applies
+		var instance *Occurrence
+		i := sort.Search(len(ranges), func(i int) bool {
+			return ranges[i].rid.After(t)
+		})
+		if i == 0 {
+			instance, err = instanceOccurrence(group.master, occ, t)
+		} else if r := ranges[i-1]; r.occ != nil {
+			instance, err = instanceOccurrence(r.occ.Component, r.occ, t.Add(r.occ.Start.Sub(r.rid)))
+		} else {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		instance.RecurrenceID = t
+		if instance.overlaps(start, end) {
+			l = append(l, *instance)
+		}
+	}
+	return l, nil
+}
+
+// newOccurrence returns the occurrence described by the DTSTART, DTEND and
+// DURATION properties of an event, or nil if the event is cancelled.
+func newOccurrence(comp *Component, rid time.Time, loc *time.Location, load timezoneLoader) (*Occurrence, error) {
+	if status, err := comp.Props.Text(PropStatus); err != nil {
+		return nil, err
+	} else if EventStatus(strings.ToUpper(status)) == EventCancelled {
+		return nil, nil
+	}
+
+	occ := &Occurrence{RecurrenceID: rid, Component: comp}
+	prop := comp.Props.Get(PropDateTimeStart)
+	if prop == nil {
+		// Overrides may inherit the start of the instance
+		if rid.IsZero() {
+			return nil, fmt.Errorf("ical: missing DTSTART in VEVENT")
+		}
+		occ.Start = rid
+	} else {
+		var err error
+		occ.Start, err = prop.dateTime(prop.Value, loc, load)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if prop := comp.Props.Get(PropDateTimeEnd); prop != nil {
+		var err error
+		occ.End, err = prop.dateTime(prop.Value, loc, load)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		dur, err := eventDuration(comp)
+		if err != nil {
+			return nil, err
+		}
+		occ.End = dur.AddTo(occ.Start)
+	}
+	return occ, nil
+}
+
+// eventDuration returns the nominal duration of an event without DTEND.
+func eventDuration(comp *Component) (Duration, error) {
+	if prop := comp.Props.Get(PropDuration); prop != nil {
+		return prop.Duration()
+	}
+	if prop := comp.Props.Get(PropDateTimeStart); prop != nil && prop.isDate() {
+		return Duration{Days: 1}, nil
+	}
+	return Duration{}, nil
+}
+
+// instanceOccurrence returns the instance of a recurring master component
+// starting at t. Instances have the same exact duration as the master
+// component, unless it is a nominal duration in days, as specified in RFC
+// 5545 section 3.8.5.3.
+func instanceOccurrence(master *Component, occ *Occurrence, t time.Time) (*Occurrence, error) {
+	instance := &Occurrence{
+		Start:        t,
+		RecurrenceID: t,
+		Component:    master,
+	}
+
+	t = t.In(occ.Start.Location())
+	if master.Props.Get(PropDateTimeEnd) == nil {
+		dur, err := eventDuration(master)
+		if err != nil {
+			return nil, err
+		}
+		instance.End = dur.AddTo(t)
+	} else if prop := master.Props.Get(PropDateTimeStart); prop != nil && prop.isDate() {
+		days := int(occ.End.Sub(occ.Start).Hours()/24 + 0.5)
+		instance.End = t.AddDate(0, 0, days)
+	} else {
+		instance.End = t.Add(occ.End.Sub(occ.Start))
+	}
+	return instance, nil
+}
+
+// instances returns the start times of the instances of a component from
+// from onwards, in chronological order, or nil if the component isn't
+// recurring. Unlike Component.RecurrenceSet, components with recurrence dates
+// but without a recurrence rule are recurring. Instances are computed lazily.
+func instances(comp *Component, loc *time.Location, load timezoneLoader, from time.Time) (func() (time.Time, bool), error) {
+	recur, err := comp.Props.Recur()
+	if err != nil {
+		return nil, fmt.Errorf("ical: error parsing recurrence: %v", err)
+	}
+	if recur == nil && comp.Props.Get(PropRecurrenceDates) == nil {
+		return nil, nil
+	}
+
+	prop := comp.Props.Get(PropDateTimeStart)
+	if prop == nil {
+		return nil, fmt.Errorf("ical: missing DTSTART in %v", comp.Name)
+	}
+	dtstart, err := prop.DateTimeValue()
+	var start time.Time
+	if err == nil {
+		start, err = dtstart.time(loc, load)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ical: error parsing start time: %v", err)
+	}
+
+	var dates rrule.Set
+	if err := comp.addRecurrenceDates(&dates, loc, load); err != nil {
+		return nil, err
+	}
+
+	rule := func() (time.Time, bool) {
+		return time.Time{}, false
+	}
+	if recur != nil {
+		rule, err = recur.occurrences(dtstart.Wall, start.Location(), from)
+		if err != nil {
+			return nil, fmt.Errorf("ical: error parsing recurrence: %v", err)
+		}
+	} else {
+		dates.RDate(start)
+	}
+	return mergeOccurrences(rule, &dates), nil
+}
+
+// occurrences returns the occurrences of a rule starting at a wall clock
+// time, expressed in UTC, in loc. Only the occurrences from from onwards are
+// guaranteed to be returned.
+func (r *Recur) occurrences(wall time.Time, loc *time.Location, from time.Time) (func() (time.Time, bool), error) {
+	if r.needsRScale() {
+		it, err := r.rscaleIterator(localTime(wall, loc))
+		if err != nil {
+			return nil, err
+		}
+		return it.next, nil
+	}
+	roption, err := r.ROption(loc)
+	if err != nil {
+		return nil, err
+	}
+	rule := zonedRule{roption: *roption, wall: wall, loc: loc}
+	return rule.iterator(from)
+}
@@ -0,0 +1,234 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+var occurrencesCalendarStr = toCRLF(`BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//xyz Corp//NONSGML PDA Calendar Version 1.0//EN
+BEGIN:VEVENT
+UID:daily@example.org
+DTSTAMP:20240101T000000Z
+DTSTART:20240101T100000Z
+DTEND:20240101T110000Z
+RRULE:FREQ=DAILY;COUNT=5
+EXDATE:20240102T100000Z
+SUMMARY:Daily
+END:VEVENT
+BEGIN:VEVENT
+UID:all-day@example.org
+DTSTAMP:20240101T000000Z
+DTSTART;VALUE=DATE:20240102
+SUMMARY:All day
+END:VEVENT
+BEGIN:VEVENT
+UID:daily@example.org
+DTSTAMP:20240101T000000Z
+RECURRENCE-ID:20240103T100000Z
+DTSTART:20240103T150000Z
+DTEND:20240103T170000Z
+SUMMARY:Moved
+END:VEVENT
+BEGIN:VEVENT
+UID:daily@example.org
+DTSTAMP:20240101T000000Z
+RECURRENCE-ID:20240104T100000Z
+DTSTART:20240104T100000Z
+STATUS:CANCELLED
+END:VEVENT
+BEGIN:VEVENT
+UID:cancelled@example.org
+DTSTAMP:20240101T000000Z
+DTSTART:20240101T120000Z
+STATUS:CANCELLED
+END:VEVENT
+END:VCALENDAR
+`)
+
+func TestCalendarOccurrences(t *testing.T) {
+	cal, err := NewDecoder(strings.NewReader(occurrencesCalendarStr)).Decode()
+	if err != nil {
+		t.Fatalf("Decode() = %v", err)
+	}
+
+	type occurrence struct {
+		start, end, rid time.Time
+		summary         string
+	}
+	date := func(day, hour int) time.Time {
+		return time.Date(2024, 1, day, hour, 0, 0, 0, time.UTC)
+	}
+	testCases := []struct {
+		name       string
+		start, end time.Time
+		want       []occurrence
+	}{
+		{
+			name:  "all",
+			start: date(1, 0),
+			end:   date(10, 0),
+			want: []occurrence{
+				{date(1, 10), date(1, 11), date(1, 10), "Daily"},
+				{date(2, 0), date(3, 0), time.Time{}, "All day"},
+				{date(3, 15), date(3, 17), date(3, 10), "Moved"},
+				{date(5, 10), date(5, 11), date(5, 10), "Daily"},
+			},
+		},
+		{
+			name:  "overlapping start",
+			start: date(1, 10).Add(30 * time.Minute),
+			end:   date(2, 12),
+			want: []occurrence{
+				{date(1, 10), date(1, 11), date(1, 10), "Daily"},
+				{date(2, 0), date(3, 0), time.Time{}, "All day"},
+			},
+		},
+		{
+			name:  "overridden instance",
+			start: date(3, 9),
+			end:   date(3, 12),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			l, err := cal.Occurrences(tc.start, tc.end)
+			if err != nil {
+				t.Fatalf("Calendar.Occurrences() = %v", err)
+			}
+			if len(l) != len(tc.want) {
+				t.Fatalf("Calendar.Occurrences() = %v occurrences, want %v", len(l), len(tc.want))
+			}
+			for i, occ := range l {
+				want := tc.want[i]
+				summary, _ := occ.Component.Props.Text(PropSummary)
+				if !occ.Start.Equal(want.start) || !occ.End.Equal(want.end) || !occ.RecurrenceID.Equal(want.rid) || summary != want.summary {
+					t.Errorf("occurrence %v = %v-%v (%v, %q), want %v-%v (%v, %q)", i, occ.Start, occ.End, occ.RecurrenceID, summary, want.start, want.end, want.rid, want.summary)
+				}
+			}
+		})
+	}
+}
+
+func TestCalendarOccurrencesAllDay(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+
+	cal := NewCalendar()
+	event := NewEvent()
+	event.Props.SetText(PropUID, "all-day@example.org")
+	event.Props.SetDate(PropDateTimeStart, time.Date(2024, 3, 29, 0, 0, 0, 0, time.UTC))
+	event.Props.SetDate(PropDateTimeEnd, time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC))
+	event.Props.Set(&Prop{Name: PropRecurrenceRule, Params: make(Params), Value: "FREQ=DAILY;COUNT=3"})
+	cal.Children = append(cal.Children, event.Component)
+
+	// The instance starting on 2024-03-30 lasts two days across DST
+	l, err := cal.Occurrences(time.Date(2024, 3, 30, 12, 0, 0, 0, paris), time.Date(2024, 3, 31, 12, 0, 0, 0, paris))
+	if err != nil {
+		t.Fatalf("Calendar.Occurrences() = %v", err)
+	}
+	if len(l) != 3 {
+		t.Fatalf("Calendar.Occurrences() = %v occurrences, want 3", len(l))
+	}
+	if want := time.Date(2024, 4, 1, 0, 0, 0, 0, paris); !l[1].End.Equal(want) {
+		t.Errorf("occurrence end = %v, want %v", l[1].End, want)
+	}
+}
+
+func TestCalendarOccurrencesThisAndFuture(t *testing.T) {
+	cal, err := NewDecoder(strings.NewReader(toCRLF(`BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//xyz Corp//NONSGML PDA Calendar Version 1.0//EN
+BEGIN:VEVENT
+UID:daily@example.org
+DTSTAMP:20240101T000000Z
+DTSTART:20240101T100000Z
+DTEND:20240101T110000Z
+RRULE:FREQ=DAILY;COUNT=7
+SUMMARY:Daily
+END:VEVENT
+BEGIN:VEVENT
+UID:daily@example.org
+DTSTAMP:20240101T000000Z
+RECURRENCE-ID;RANGE=THISANDFUTURE:20240103T100000Z
+DTSTART:20240103T140000Z
+DTEND:20240103T160000Z
+SUMMARY:Afternoon
+END:VEVENT
+BEGIN:VEVENT
+UID:daily@example.org
+DTSTAMP:20240101T000000Z
+RECURRENCE-ID:20240105T100000Z
+DTSTART:20240105T090000Z
+DTEND:20240105T100000Z
+SUMMARY:Morning
+END:VEVENT
+BEGIN:VEVENT
+UID:daily@example.org
+DTSTAMP:20240101T000000Z
+RECURRENCE-ID;RANGE=THISANDFUTURE:20240106T100000Z
+DTSTART:20240106T140000Z
+STATUS:CANCELLED
+END:VEVENT
+END:VCALENDAR
+`))).Decode()
+	if err != nil {
+		t.Fatalf("Decode() = %v", err)
+	}
+
+	type occurrence struct {
+		start, end, rid time.Time
+		summary         string
+	}
+	date := func(day, hour int) time.Time {
+		return time.Date(2024, 1, day, hour, 0, 0, 0, time.UTC)
+	}
+	testCases := []struct {
+		name       string
+		start, end time.Time
+		want       []occurrence
+	}{
+		{
+			name:  "all",
+			start: date(1, 0),
+			end:   date(10, 0),
+			want: []occurrence{
+				{date(1, 10), date(1, 11), date(1, 10), "Daily"},
+				{date(2, 10), date(2, 11), date(2, 10), "Daily"},
+				{date(3, 14), date(3, 16), date(3, 10), "Afternoon"},
+				{date(4, 14), date(4, 16), date(4, 10), "Afternoon"},
+				{date(5, 9), date(5, 10), date(5, 10), "Morning"},
+			},
+		},
+		{
+			name:  "shifted instance",
+			start: date(4, 13),
+			end:   date(4, 17),
+			want: []occurrence{
+				{date(4, 14), date(4, 16), date(4, 10), "Afternoon"},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			l, err := cal.Occurrences(tc.start, tc.end)
+			if err != nil {
+				t.Fatalf("Calendar.Occurrences() = %v", err)
+			}
+			if len(l) != len(tc.want) {
+				t.Fatalf("Calendar.Occurrences() = %v occurrences, want %v", len(l), len(tc.want))
+			}
+			for i, occ := range l {
+				want := tc.want[i]
+				summary, _ := occ.Component.Props.Text(PropSummary)
+				if !occ.Start.Equal(want.start) || !occ.End.Equal(want.end) || !occ.RecurrenceID.Equal(want.rid) || summary != want.summary {
+					t.Errorf("occurrence %v = %v-%v (%v, %q), want %v-%v (%v, %q)", i, occ.Start, occ.End, occ.RecurrenceID, summary, want.start, want.end, want.rid, want.summary)
+				}
+			}
+		})
+	}
+}
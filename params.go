@@ -0,0 +1,195 @@
+package ical
+
+import (
+	"fmt"
+	"strings"
+)
+
+func setParam(params Params, name, value string) {
+	if value != "" {
+		params.Set(name, value)
+	} else {
+		params.Del(name)
+	}
+}
+
+// isExtensionToken checks whether s is an iana-token or an x-name, as defined
+// in RFC 5545 section 3.1.
+func isExtensionToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z') && !(r >= 'A' && r <= 'Z') && !(r >= '0' && r <= '9') && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// enumParam fetches a case-insensitive enumerated parameter value. If the
+// value isn't part of allowed, it's only accepted if extensible is set and
+// the value is an extension token.
+func (params Params) enumParam(name string, allowed []string, extensible bool) (string, error) {
+	v := strings.ToUpper(params.Get(name))
+	if err := checkEnumParam(name, v, allowed, extensible); err != nil {
+		return "", err
+	}
+	return v, nil
+}
+
+func (params Params) setEnumParam(name, v string, allowed []string, extensible bool) error {
+	v = strings.ToUpper(v)
+	if err := checkEnumParam(name, v, allowed, extensible); err != nil {
+		return err
+	}
+	setParam(params, name, v)
+	return nil
+}
+
+func checkEnumParam(name, v string, allowed []string, extensible bool) error {
+	if v == "" {
+		return nil
+	}
+	for _, s := range allowed {
+		if v == s {
+			return nil
+		}
+	}
+	if extensible && isExtensionToken(v) {
+		return nil
+	}
+	return fmt.Errorf("ical: invalid %v parameter: %q", name, v)
+}
+
+var (
+	calendarUserTypes = []string{
+		string(CalendarUserIndividual),
+		string(CalendarUserGroup),
+		string(CalendarUserResource),
+		string(CalendarUserRoom),
+		string(CalendarUserUnknown),
+	}
+	freeBusyTypes = []string{
+		string(FreeBusyFree),
+		string(FreeBusyBusy),
+		string(FreeBusyBusyUnavailable),
+		string(FreeBusyBusyTentative),
+	}
+	relationshipTypes = []string{
+		string(RelationshipParent),
+		string(RelationshipChild),
+		string(RelationshipSibling),
+	}
+	roles = []string{
+		string(RoleChair),
+		string(RoleReqParticipant),
+		string(RoleOptParticipant),
+		string(RoleNonParticipant),
+	}
+	relateds = []string{string(RelatedStart), string(RelatedEnd)}
+	ranges   = []string{string(RangeThisAndFuture)}
+)
+
+// CalendarUserType returns the CUTYPE parameter, or an empty string if it's
+// absent.
+func (params Params) CalendarUserType() (CalendarUserType, error) {
+	v, err := params.enumParam(ParamCalendarUserType, calendarUserTypes, true)
+	return CalendarUserType(v), err
+}
+
+func (params Params) SetCalendarUserType(t CalendarUserType) error {
+	return params.setEnumParam(ParamCalendarUserType, string(t), calendarUserTypes, true)
+}
+
+// FreeBusyType returns the FBTYPE parameter, or an empty string if it's
+// absent.
+func (params Params) FreeBusyType() (FreeBusyType, error) {
+	v, err := params.enumParam(ParamFreeBusyType, freeBusyTypes, true)
+	return FreeBusyType(v), err
+}
+
+func (params Params) SetFreeBusyType(t FreeBusyType) error {
+	return params.setEnumParam(ParamFreeBusyType, string(t), freeBusyTypes, true)
+}
+
+// ParticipationStatus returns the PARTSTAT parameter, or an empty string if
+// it's absent. compName is the kind of the component the property belongs
+// to, and is used to check whether the value is allowed.
+func (params Params) ParticipationStatus(compName string) (ParticipationStatus, error) {
+	status := ParticipationStatus(strings.ToUpper(params.Get(ParamParticipationStatus)))
+	if status != "" && !status.ValidFor(compName) {
+		return "", fmt.Errorf("ical: invalid %v parameter: %q", ParamParticipationStatus, status)
+	}
+	return status, nil
+}
+
+func (params Params) SetParticipationStatus(compName string, status ParticipationStatus) error {
+	status = ParticipationStatus(strings.ToUpper(string(status)))
+	if status != "" && !status.ValidFor(compName) {
+		return fmt.Errorf("ical: invalid %v parameter: %q", ParamParticipationStatus, status)
+	}
+	setParam(params, ParamParticipationStatus, string(status))
+	return nil
+}
+
+// RelationshipType returns the RELTYPE parameter, or an empty string if it's
+// absent.
+func (params Params) RelationshipType() (RelationshipType, error) {
+	v, err := params.enumParam(ParamRelationshipType, relationshipTypes, true)
+	return RelationshipType(v), err
+}
+
+func (params Params) SetRelationshipType(t RelationshipType) error {
+	return params.setEnumParam(ParamRelationshipType, string(t), relationshipTypes, true)
+}
+
+// Role returns the ROLE parameter, or an empty string if it's absent.
+func (params Params) Role() (Role, error) {
+	v, err := params.enumParam(ParamRole, roles, true)
+	return Role(v), err
+}
+
+func (params Params) SetRole(role Role) error {
+	return params.setEnumParam(ParamRole, string(role), roles, true)
+}
+
+// Related returns the RELATED parameter, or an empty string if it's absent.
+func (params Params) Related() (Related, error) {
+	v, err := params.enumParam(ParamRelated, relateds, false)
+	return Related(v), err
+}
+
+func (params Params) SetRelated(related Related) error {
+	return params.setEnumParam(ParamRelated, string(related), relateds, false)
+}
+
+// Range returns the RANGE parameter, or an empty string if it's absent.
+func (params Params) Range() (Range, error) {
+	v, err := params.enumParam(ParamRange, ranges, false)
+	return Range(v), err
+}
+
+func (params Params) SetRange(r Range) error {
+	return params.setEnumParam(ParamRange, string(r), ranges, false)
+}
+
+// RSVP returns the RSVP parameter. It defaults to false.
+func (params Params) RSVP() (bool, error) {
+	switch v := strings.ToUpper(params.Get(ParamRSVP)); v {
+	case "TRUE":
+		return true, nil
+	case "", "FALSE":
+		return false, nil
+	default:
+		return false, fmt.Errorf("ical: invalid %v parameter: %q", ParamRSVP, v)
+	}
+}
+
+func (params Params) SetRSVP(rsvp bool) {
+	if rsvp {
+		params.Set(ParamRSVP, "TRUE")
+	} else {
+		params.Set(ParamRSVP, "FALSE")
+	}
+}
@@ -0,0 +1,100 @@
+package ical
+
+import (
+	"testing"
+)
+
+func TestParticipationStatus(t *testing.T) {
+	testCases := []struct {
+		Value    string
+		CompName string
+		Expected ParticipationStatus
+		Valid    bool
+	}{
+		{"accepted", CompEvent, ParticipationAccepted, true},
+		{"COMPLETED", CompToDo, ParticipationCompleted, true},
+		{"COMPLETED", CompEvent, "", false},
+		{"TENTATIVE", CompJournal, "", false},
+		{"IN-PROCESS", "", ParticipationInProcess, true},
+		{"X-MAYBE", CompJournal, "X-MAYBE", true},
+		{"NEW-STATUS", CompEvent, "NEW-STATUS", true},
+		{"not a token", CompEvent, "", false},
+		{"", CompEvent, "", true},
+	}
+
+	for _, tCase := range testCases {
+		params := make(Params)
+		if tCase.Value != "" {
+			params.Set(ParamParticipationStatus, tCase.Value)
+		}
+
+		status, err := params.ParticipationStatus(tCase.CompName)
+		if tCase.Valid && err != nil {
+			t.Errorf("Params.ParticipationStatus(%q) with %q = %v", tCase.CompName, tCase.Value, err)
+		} else if !tCase.Valid && err == nil {
+			t.Errorf("Params.ParticipationStatus(%q) with %q = nil, want an error", tCase.CompName, tCase.Value)
+		} else if status != tCase.Expected {
+			t.Errorf("Params.ParticipationStatus(%q) with %q = %v, want %v", tCase.CompName, tCase.Value, status, tCase.Expected)
+		}
+
+		params = make(Params)
+		err = params.SetParticipationStatus(tCase.CompName, ParticipationStatus(tCase.Value))
+		if tCase.Valid && err != nil {
+			t.Errorf("Params.SetParticipationStatus(%q, %q) = %v", tCase.CompName, tCase.Value, err)
+		} else if !tCase.Valid && err == nil {
+			t.Errorf("Params.SetParticipationStatus(%q, %q) = nil, want an error", tCase.CompName, tCase.Value)
+		}
+	}
+}
+
+func TestEnumParams(t *testing.T) {
+	params := make(Params)
+
+	if err := params.SetRole(RoleChair); err != nil {
+		t.Errorf("Params.SetRole() = %v", err)
+	}
+	if role, err := params.Role(); err != nil || role != RoleChair {
+		t.Errorf("Params.Role() = %v, %v, want %v", role, err, RoleChair)
+	}
+	if err := params.SetRole("X-OBSERVER"); err != nil {
+		t.Errorf("Params.SetRole(X-OBSERVER) = %v", err)
+	}
+
+	params.Set(ParamCalendarUserType, "room")
+	if cutype, err := params.CalendarUserType(); err != nil || cutype != CalendarUserRoom {
+		t.Errorf("Params.CalendarUserType() = %v, %v, want %v", cutype, err, CalendarUserRoom)
+	}
+
+	if err := params.SetFreeBusyType(FreeBusyBusyTentative); err != nil {
+		t.Errorf("Params.SetFreeBusyType() = %v", err)
+	}
+	if got := params.Get(ParamFreeBusyType); got != "BUSY-TENTATIVE" {
+		t.Errorf("FBTYPE = %v, want BUSY-TENTATIVE", got)
+	}
+
+	if reltype, err := params.RelationshipType(); err != nil || reltype != "" {
+		t.Errorf("Params.RelationshipType() = %v, %v, want empty", reltype, err)
+	}
+
+	// RELATED and RANGE aren't extensible
+	if err := params.SetRelated("X-MIDDLE"); err == nil {
+		t.Errorf("Params.SetRelated(X-MIDDLE) = nil, want an error")
+	}
+	params.Set(ParamRelated, "end")
+	if related, err := params.Related(); err != nil || related != RelatedEnd {
+		t.Errorf("Params.Related() = %v, %v, want %v", related, err, RelatedEnd)
+	}
+	params.Set(ParamRange, "THISANDPRIOR")
+	if _, err := params.Range(); err == nil {
+		t.Errorf("Params.Range() with THISANDPRIOR = nil, want an error")
+	}
+
+	params.SetRSVP(true)
+	if rsvp, err := params.RSVP(); err != nil || !rsvp {
+		t.Errorf("Params.RSVP() = %v, %v, want true", rsvp, err)
+	}
+	params.Set(ParamRSVP, "YES")
+	if _, err := params.RSVP(); err == nil {
+		t.Errorf("Params.RSVP() with YES = nil, want an error")
+	}
+}
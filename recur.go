@@ -0,0 +1,662 @@
+package ical
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/teambition/rrule-go"
+)
+
+// Frequency is the frequency of a recurrence rule, defined in RFC 5545
+// section 3.3.10.
+type Frequency string
+
+const (
+	FrequencySecondly Frequency = "SECONDLY"
+	FrequencyMinutely Frequency = "MINUTELY"
+	FrequencyHourly   Frequency = "HOURLY"
+	FrequencyDaily    Frequency = "DAILY"
+	FrequencyWeekly   Frequency = "WEEKLY"
+	FrequencyMonthly  Frequency = "MONTHLY"
+	FrequencyYearly   Frequency = "YEARLY"
+)
+
+var frequencies = map[Frequency]rrule.Frequency{
+	FrequencySecondly: rrule.SECONDLY,
+	FrequencyMinutely: rrule.MINUTELY,
+	FrequencyHourly:   rrule.HOURLY,
+	FrequencyDaily:    rrule.DAILY,
+	FrequencyWeekly:   rrule.WEEKLY,
+	FrequencyMonthly:  rrule.MONTHLY,
+	FrequencyYearly:   rrule.YEARLY,
+}
+
+// Weekday is a day of the week, as used in recurrence rules.
+type Weekday string
+
+const (
+	Monday    Weekday = "MO"
+	Tuesday   Weekday = "TU"
+	Wednesday Weekday = "WE"
+	Thursday  Weekday = "TH"
+	Friday    Weekday = "FR"
+	Saturday  Weekday = "SA"
+	Sunday    Weekday = "SU"
+)
+
+var weekdays = map[Weekday]time.Weekday{
+	Monday:    time.Monday,
+	Tuesday:   time.Tuesday,
+	Wednesday: time.Wednesday,
+	Thursday:  time.Thursday,
+	Friday:    time.Friday,
+	Saturday:  time.Saturday,
+	Sunday:    time.Sunday,
+}
+
+var rruleWeekdays = map[Weekday]rrule.Weekday{
+	Monday:    rrule.MO,
+	Tuesday:   rrule.TU,
+	Wednesday: rrule.WE,
+	Thursday:  rrule.TH,
+	Friday:    rrule.FR,
+	Saturday:  rrule.SA,
+	Sunday:    rrule.SU,
+}
+
+// WeekdayNum is a day of the week with an optional ordinal, e.g. "-1SU" for
+// the last Sunday. N is zero if there is no ordinal.
+type WeekdayNum struct {
+	N   int
+	Day Weekday
+}
+
+func (wd WeekdayNum) String() string {
+	if wd.N == 0 {
+		return string(wd.Day)
+	}
+	return strconv.Itoa(wd.N) + string(wd.Day)
+}
+
+func parseWeekday(s string) (Weekday, error) {
+	wd := Weekday(s)
+	if _, ok := weekdays[wd]; !ok {
+		return "", fmt.Errorf("ical: invalid weekday: %q", s)
+	}
+	return wd, nil
+}
+
+func parseWeekdayNum(s string) (WeekdayNum, error) {
+	if len(s) < 2 {
+		return WeekdayNum{}, fmt.Errorf("ical: invalid weekday: %q", s)
+	}
+
+	day, err := parseWeekday(s[len(s)-2:])
+	if err != nil {
+		return WeekdayNum{}, err
+	}
+
+	var n int
+	if ord := s[:len(s)-2]; ord != "" {
+		n, err = strconv.Atoi(ord)
+		if err != nil || n == 0 || n < -53 || n > 53 {
+			return WeekdayNum{}, fmt.Errorf("ical: invalid weekday ordinal: %q", s)
+		}
+	}
+
+	return WeekdayNum{N: n, Day: day}, nil
+}
+
+// RecurMonth is a month of the year, as used in the BYMONTH rule part. Leap
+// is set for leap months, which only exist in some calendar systems, see RFC
+// 7529 section 4.2.
+type RecurMonth struct {
+	Month int
+	Leap  bool
+}
+
+func (month RecurMonth) String() string {
+	s := strconv.Itoa(month.Month)
+	if month.Leap {
+		s += "L"
+	}
+	return s
+}
+
+// Skip describes how invalid dates generated by a recurrence rule are
+// handled, defined in RFC 7529 section 4.1.
+type Skip string
+
+const (
+	SkipOmit     Skip = "OMIT"
+	SkipBackward Skip = "BACKWARD"
+	SkipForward  Skip = "FORWARD"
+)
+
+// RecurPart is a recurrence rule part unknown to this package, e.g. an
+// x-name.
+type RecurPart struct {
+	Name, Value string
+}
+
+// Recur is a recurrence rule, defined in RFC 5545 section 3.3.10.
+//
+// Zero values denote absent rule parts. Parsing and formatting a rule
+// preserves the order of its parts.
+type Recur struct {
+	// RScale is the calendar system the rule is evaluated in, defined in RFC
+	// 7529. An empty value is equivalent to "GREGORIAN".
+	RScale string
+	Freq   Frequency
+	// Until is the inclusive end of the recurrence, or the zero time if
+	// unset. UntilDate is set if UNTIL is a DATE, and UntilFloating if it's
+	// a DATE-TIME without the UTC designator. In both cases Until holds the
+	// wall-clock value in UTC.
+	Until         time.Time
+	UntilDate     bool
+	UntilFloating bool
+	Count         int
+	Interval      int
+	BySecond      []int
+	ByMinute      []int
+	ByHour        []int
+	ByDay         []WeekdayNum
+	ByMonthDay    []int
+	ByYearDay     []int
+	ByWeekNo      []int
+	ByMonth       []RecurMonth
+	BySetPos      []int
+	WeekStart     Weekday
+	// Skip is only allowed if RScale is set. An empty value is equivalent to
+	// SkipOmit.
+	Skip Skip
+	// Extra contains rule parts unknown to this package.
+	Extra []RecurPart
+
+	// Names of the rule parts, in the order they were parsed
+	order []string
+}
+
+// Rule part names in their canonical order.
+var recurPartNames = []string{
+	"RSCALE",
+	"FREQ",
+	"UNTIL",
+	"COUNT",
+	"INTERVAL",
+	"BYSECOND",
+	"BYMINUTE",
+	"BYHOUR",
+	"BYDAY",
+	"BYMONTHDAY",
+	"BYYEARDAY",
+	"BYWEEKNO",
+	"BYMONTH",
+	"BYSETPOS",
+	"WKST",
+	"SKIP",
+}
+
+func parseIntList(name, s string, min, max int, signed bool) ([]int, error) {
+	var l []int
+	for _, v := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("ical: invalid %v value: %q", name, v)
+		}
+		abs := n
+		if signed && n < 0 {
+			abs = -n
+		}
+		if abs < min || abs > max || (!signed && n < 0) {
+			return nil, fmt.Errorf("ical: invalid %v value: %v out of range", name, n)
+		}
+		l = append(l, n)
+	}
+	return l, nil
+}
+
+func formatIntList(l []int) string {
+	values := make([]string, len(l))
+	for i, n := range l {
+		values[i] = strconv.Itoa(n)
+	}
+	return strings.Join(values, ",")
+}
+
+// ParseRecur parses a recurrence rule, e.g. "FREQ=DAILY;COUNT=10".
+func ParseRecur(s string) (*Recur, error) {
+	r := &Recur{}
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ";") {
+		i := strings.IndexByte(part, '=')
+		if i <= 0 {
+			return nil, fmt.Errorf("ical: malformed recurrence rule part: %q", part)
+		}
+		name, value := strings.ToUpper(part[:i]), part[i+1:]
+		if value == "" {
+			return nil, fmt.Errorf("ical: malformed recurrence rule part: %v has no value", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("ical: malformed recurrence rule: duplicate %v", name)
+		}
+		seen[name] = true
+		r.order = append(r.order, name)
+
+		if err := r.parsePart(name, value); err != nil {
+			return nil, err
+		}
+	}
+
+	if r.Freq == "" {
+		return nil, fmt.Errorf("ical: malformed recurrence rule: missing FREQ")
+	}
+	if r.Count != 0 && !r.Until.IsZero() {
+		return nil, fmt.Errorf("ical: malformed recurrence rule: both COUNT and UNTIL are specified")
+	}
+	if r.Skip != "" && r.RScale == "" {
+		return nil, fmt.Errorf("ical: malformed recurrence rule: SKIP requires RSCALE")
+	}
+
+	return r, nil
+}
+
+func (r *Recur) parsePart(name, value string) error {
+	var err error
+	switch name {
+	case "FREQ":
+		r.Freq = Frequency(strings.ToUpper(value))
+		if _, ok := frequencies[r.Freq]; !ok {
+			return fmt.Errorf("ical: invalid recurrence frequency: %q", value)
+		}
+	case "UNTIL":
+		value = strings.ToUpper(value)
+		switch len(value) {
+		case len(dateFormat):
+			r.Until, err = time.ParseInLocation(dateFormat, value, time.UTC)
+			r.UntilDate = true
+		case len(datetimeFormat):
+			r.Until, err = time.ParseInLocation(datetimeFormat, value, time.UTC)
+			r.UntilFloating = true
+		default:
+			r.Until, err = time.ParseInLocation(datetimeUTCFormat, value, time.UTC)
+		}
+		if err != nil {
+			return fmt.Errorf("ical: invalid recurrence UNTIL: %v", err)
+		}
+	case "COUNT":
+		r.Count, err = strconv.Atoi(value)
+		if err != nil || r.Count <= 0 {
+			return fmt.Errorf("ical: invalid recurrence COUNT: %q", value)
+		}
+	case "INTERVAL":
+		r.Interval, err = strconv.Atoi(value)
+		if err != nil || r.Interval <= 0 {
+			return fmt.Errorf("ical: invalid recurrence INTERVAL: %q", value)
+		}
+	case "BYSECOND":
+		r.BySecond, err = parseIntList(name, value, 0, 60, false)
+	case "BYMINUTE":
+		r.ByMinute, err = parseIntList(name, value, 0, 59, false)
+	case "BYHOUR":
+		r.ByHour, err = parseIntList(name, value, 0, 23, false)
+	case "BYDAY":
+		for _, v := range strings.Split(strings.ToUpper(value), ",") {
+			wd, err := parseWeekdayNum(v)
+			if err != nil {
+				return err
+			}
+			r.ByDay = append(r.ByDay, wd)
+		}
+	case "BYMONTHDAY":
+		r.ByMonthDay, err = parseIntList(name, value, 1, 31, true)
+	case "BYYEARDAY":
+		r.ByYearDay, err = parseIntList(name, value, 1, 366, true)
+	case "BYWEEKNO":
+		r.ByWeekNo, err = parseIntList(name, value, 1, 53, true)
+	case "BYMONTH":
+		for _, v := range strings.Split(strings.ToUpper(value), ",") {
+			month := RecurMonth{Leap: strings.HasSuffix(v, "L")}
+			month.Month, err = strconv.Atoi(strings.TrimSuffix(v, "L"))
+			if err != nil || month.Month < 1 || month.Month > 12 {
+				return fmt.Errorf("ical: invalid BYMONTH value: %q", v)
+			}
+			r.ByMonth = append(r.ByMonth, month)
+		}
+	case "BYSETPOS":
+		r.BySetPos, err = parseIntList(name, value, 1, 366, true)
+	case "WKST":
+		r.WeekStart, err = parseWeekday(strings.ToUpper(value))
+	case "RSCALE":
+		r.RScale = strings.ToUpper(value)
+		if !isExtensionToken(r.RScale) {
+			return fmt.Errorf("ical: invalid recurrence RSCALE: %q", value)
+		}
+	case "SKIP":
+		switch r.Skip = Skip(strings.ToUpper(value)); r.Skip {
+		case SkipOmit, SkipBackward, SkipForward:
+			// ok
+		default:
+			return fmt.Errorf("ical: invalid recurrence SKIP: %q", value)
+		}
+	default:
+		if !isExtensionToken(name) {
+			return fmt.Errorf("ical: malformed recurrence rule part name: %q", name)
+		}
+		r.Extra = append(r.Extra, RecurPart{Name: name, Value: value})
+	}
+	return err
+}
+
+// formatPart formats a rule part. An empty string is returned if the part is
+// absent.
+func (r *Recur) formatPart(name string) string {
+	switch name {
+	case "RSCALE":
+		return r.RScale
+	case "FREQ":
+		return string(r.Freq)
+	case "UNTIL":
+		switch {
+		case r.Until.IsZero():
+			return ""
+		case r.UntilDate:
+			return r.Until.Format(dateFormat)
+		case r.UntilFloating:
+			return r.Until.Format(datetimeFormat)
+		default:
+			return r.Until.UTC().Format(datetimeUTCFormat)
+		}
+	case "COUNT":
+		if r.Count == 0 {
+			return ""
+		}
+		return strconv.Itoa(r.Count)
+	case "INTERVAL":
+		if r.Interval == 0 {
+			return ""
+		}
+		return strconv.Itoa(r.Interval)
+	case "BYSECOND":
+		return formatIntList(r.BySecond)
+	case "BYMINUTE":
+		return formatIntList(r.ByMinute)
+	case "BYHOUR":
+		return formatIntList(r.ByHour)
+	case "BYDAY":
+		values := make([]string, len(r.ByDay))
+		for i, wd := range r.ByDay {
+			values[i] = wd.String()
+		}
+		return strings.Join(values, ",")
+	case "BYMONTHDAY":
+		return formatIntList(r.ByMonthDay)
+	case "BYYEARDAY":
+		return formatIntList(r.ByYearDay)
+	case "BYWEEKNO":
+		return formatIntList(r.ByWeekNo)
+	case "BYMONTH":
+		values := make([]string, len(r.ByMonth))
+		for i, month := range r.ByMonth {
+			values[i] = month.String()
+		}
+		return strings.Join(values, ",")
+	case "BYSETPOS":
+		return formatIntList(r.BySetPos)
+	case "WKST":
+		return string(r.WeekStart)
+	case "SKIP":
+		return string(r.Skip)
+	}
+	for _, part := range r.Extra {
+		if part.Name == name {
+			return part.Value
+		}
+	}
+	return ""
+}
+
+// String formats the recurrence rule. Rule parts are written in the order
+// they were parsed in, followed by any other part in the order suggested by
+// RFC 5545.
+func (r *Recur) String() string {
+	names := make([]string, 0, len(r.order)+len(recurPartNames)+len(r.Extra))
+	names = append(names, r.order...)
+	names = append(names, recurPartNames...)
+	for _, part := range r.Extra {
+		names = append(names, part.Name)
+	}
+
+	var parts []string
+	done := make(map[string]bool)
+	for _, name := range names {
+		if done[name] {
+			continue
+		}
+		done[name] = true
+		if v := r.formatPart(name); v != "" {
+			parts = append(parts, name+"="+v)
+		}
+	}
+	return strings.Join(parts, ";")
+}
+
+// ROption converts the recurrence rule to an rrule-go option, suitable for
+// expansion. DATE and floating UNTIL values are interpreted in loc.
+func (r *Recur) ROption(loc *time.Location) (*rrule.ROption, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	for _, part := range r.Extra {
+		if !strings.HasPrefix(part.Name, "X-") {
+			return nil, fmt.Errorf("ical: unsupported recurrence rule part: %v", part.Name)
+		}
+	}
+
+	if r.needsRScale() {
+		return nil, fmt.Errorf("ical: recurrence rule with RSCALE=%v and SKIP=%v cannot be converted", r.RScale, r.Skip)
+	}
+
+	freq, ok := frequencies[r.Freq]
+	if !ok {
+		return nil, fmt.Errorf("ical: invalid recurrence frequency: %q", r.Freq)
+	}
+
+	roption := &rrule.ROption{
+		Freq:       freq,
+		Interval:   r.Interval,
+		Count:      r.Count,
+		Bysetpos:   r.BySetPos,
+		Bymonthday: r.ByMonthDay,
+		Byyearday:  r.ByYearDay,
+		Byweekno:   r.ByWeekNo,
+		Byhour:     r.ByHour,
+		Byminute:   r.ByMinute,
+		Bysecond:   r.BySecond,
+	}
+
+	roption.Until = r.until(loc)
+	for _, month := range r.ByMonth {
+		roption.Bymonth = append(roption.Bymonth, month.Month)
+	}
+	if r.WeekStart != "" {
+		roption.Wkst = rruleWeekdays[r.WeekStart]
+	}
+	for _, wd := range r.ByDay {
+		day := rruleWeekdays[wd.Day]
+		roption.Byweekday = append(roption.Byweekday, day.Nth(wd.N))
+	}
+
+	return roption, nil
+}
+
+// explicitROption returns rule options starting at dtstart, with the rule
+// parts rrule-go derives from DTSTART made explicit. The occurrences of the
+// rule don't depend on DTSTART anymore, apart from the phase of INTERVAL and
+// COUNT.
+func explicitROption(option rrule.ROption, dtstart time.Time) rrule.ROption {
+	option.Dtstart = dtstart
+	if len(option.Byweekno) == 0 && len(option.Byyearday) == 0 && len(option.Bymonthday) == 0 && len(option.Byweekday) == 0 && len(option.Byeaster) == 0 {
+		switch option.Freq {
+		case rrule.YEARLY:
+			if len(option.Bymonth) == 0 {
+				option.Bymonth = []int{int(dtstart.Month())}
+			}
+			option.Bymonthday = []int{dtstart.Day()}
+		case rrule.MONTHLY:
+			option.Bymonthday = []int{dtstart.Day()}
+		case rrule.WEEKLY:
+			wd := []rrule.Weekday{rrule.SU, rrule.MO, rrule.TU, rrule.WE, rrule.TH, rrule.FR, rrule.SA}
+			option.Byweekday = []rrule.Weekday{wd[dtstart.Weekday()]}
+		}
+	}
+	if len(option.Byhour) == 0 && option.Freq < rrule.HOURLY {
+		option.Byhour = []int{dtstart.Hour()}
+	}
+	if len(option.Byminute) == 0 && option.Freq < rrule.MINUTELY {
+		option.Byminute = []int{dtstart.Minute()}
+	}
+	if len(option.Bysecond) == 0 && option.Freq < rrule.SECONDLY {
+		option.Bysecond = []int{dtstart.Second()}
+	}
+	return option
+}
+
+// seekROption returns rule options with the same occurrences from t onwards,
+// but evaluated from the start of the period of the rule containing t rather
+// than from DTSTART. The options must be returned by explicitROption and
+// must not have a COUNT. DTSTART and t are wall clock times, expressed in
+// UTC.
+func seekROption(option rrule.ROption, t time.Time) rrule.ROption {
+	start := option.Dtstart
+	if !t.After(start) {
+		return option
+	}
+	interval := option.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	const secondsPerDay = 24 * 60 * 60
+	y, m, d := start.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	var period time.Time
+	switch option.Freq {
+	case rrule.YEARLY:
+		n := (t.Year() - y) / interval * interval
+		period = time.Date(y+n, time.January, 1, 0, 0, 0, 0, time.UTC)
+	case rrule.MONTHLY:
+		n := ((t.Year()-y)*12 + int(t.Month()-m)) / interval * interval
+		period = time.Date(y, m+time.Month(n), 1, 0, 0, 0, 0, time.UTC)
+	case rrule.WEEKLY:
+		// Weeks start on WKST, rrule-go numbers weekdays from Monday
+		offset := ((int(start.Weekday())+6)%7 - option.Wkst.Day() + 7) % 7
+		week := day.AddDate(0, 0, -offset)
+		n := int((t.Unix()-week.Unix())/(7*secondsPerDay)) / interval * interval
+		period = week.AddDate(0, 0, 7*n)
+	case rrule.DAILY:
+		n := int((t.Unix()-day.Unix())/secondsPerDay) / interval * interval
+		period = day.AddDate(0, 0, n)
+	default:
+		var unit int64
+		switch option.Freq {
+		case rrule.HOURLY:
+			unit = 60 * 60
+		case rrule.MINUTELY:
+			unit = 60
+		default:
+			unit = 1
+		}
+		first := start.Unix() / unit * unit
+		n := (t.Unix() - first) / unit / int64(interval) * int64(interval)
+		period = time.Unix(first+n*unit, 0).UTC()
+	}
+
+	if period.After(start) {
+		option.Dtstart = period
+	}
+	return option
+}
+
+// until returns the inclusive end of the recurrence, or the zero time. DATE
+// and floating UNTIL values are interpreted in loc.
+func (r *Recur) until(loc *time.Location) time.Time {
+	y, m, d := r.Until.Date()
+	switch {
+	case r.Until.IsZero():
+		return time.Time{}
+	case r.UntilDate:
+		// The whole day is included
+		return localTime(time.Date(y, m, d, 23, 59, 59, 0, time.UTC), loc)
+	case r.UntilFloating:
+		return localTime(r.Until, loc)
+	default:
+		return r.Until
+	}
+}
+
+// CheckStart checks that the UNTIL rule part has the same value type as
+// DTSTART, as required by RFC 5545 section 3.3.10. It is called by
+// Encoder.Encode for VEVENT, VTODO and VJOURNAL components. Recurrence
+// expansion tolerates a mismatch: DATE and floating UNTIL values are
+// interpreted in the location of DTSTART.
+func (r *Recur) CheckStart(dtstart *Prop) error {
+	if r.Until.IsZero() || dtstart == nil {
+		return nil
+	}
+
+	switch {
+	case dtstart.isDate():
+		if !r.UntilDate {
+			return fmt.Errorf("ical: invalid recurrence rule: UNTIL must be a DATE if DTSTART is a DATE")
+		}
+	case strings.HasSuffix(strings.ToUpper(dtstart.Value), "Z") || dtstart.Params.Get(PropTimezoneID) != "":
+		if r.UntilDate || r.UntilFloating {
+			return fmt.Errorf("ical: invalid recurrence rule: UNTIL must be in UTC if DTSTART is in UTC or has a TZID")
+		}
+	default:
+		if r.UntilDate || !r.UntilFloating {
+			return fmt.Errorf("ical: invalid recurrence rule: UNTIL must be a floating DATE-TIME if DTSTART is floating")
+		}
+	}
+
+	return nil
+}
+
+// Recur parses the property value as a recurrence rule.
+func (prop *Prop) Recur() (*Recur, error) {
+	if err := prop.expectValueType(ValueRecurrence); err != nil {
+		return nil, err
+	}
+	return ParseRecur(prop.Value)
+}
+
+func (prop *Prop) SetRecur(r *Recur) {
+	prop.SetValueType(ValueRecurrence)
+	prop.Value = r.String()
+}
+
+// Recur returns the parsed RRULE property, or nil if it's absent. The UNTIL
+// rule part isn't checked against DTSTART, see Recur.CheckStart.
+func (props Props) Recur() (*Recur, error) {
+	prop := props.Get(PropRecurrenceRule)
+	if prop == nil {
+		return nil, nil
+	}
+
+	return prop.Recur()
+}
+
+// SetRecur sets the RRULE property. If r is nil, the property is removed.
+func (props Props) SetRecur(r *Recur) {
+	if r == nil {
+		props.Del(PropRecurrenceRule)
+		return
+	}
+	prop := NewProp(PropRecurrenceRule)
+	prop.SetRecur(r)
+	props.Set(prop)
+}
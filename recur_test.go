@@ -0,0 +1,159 @@
+package ical
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/teambition/rrule-go"
+)
+
+func TestRecurRoundTrip(t *testing.T) {
+	for _, s := range []string{
+		"FREQ=YEARLY;BYDAY=3SU;BYMONTH=3",
+		"BYDAY=MO,TU;FREQ=WEEKLY;INTERVAL=1;WKST=SU",
+		"FREQ=MONTHLY;BYDAY=-1FR,2MO;BYSETPOS=-1;COUNT=10",
+		"FREQ=DAILY;UNTIL=20240131;X-FOO=bar",
+		"FREQ=DAILY;UNTIL=20240131T100000",
+		"FREQ=YEARLY;UNTIL=20240131T100000Z;BYWEEKNO=20,-1;BYYEARDAY=1,-366",
+		"FREQ=MINUTELY;BYSECOND=0,60;BYMINUTE=0,59;BYHOUR=0,23;BYMONTHDAY=1,-31",
+		"RSCALE=HEBREW;FREQ=YEARLY;BYMONTH=5L;BYMONTHDAY=8;SKIP=FORWARD",
+	} {
+		r, err := ParseRecur(s)
+		if err != nil {
+			t.Errorf("ParseRecur(%q) = %v", s, err)
+			continue
+		}
+		if got := r.String(); got != s {
+			t.Errorf("ParseRecur(%q).String() = %q", s, got)
+		}
+	}
+}
+
+func TestRecurFormat(t *testing.T) {
+	r := &Recur{
+		Freq:     FrequencyMonthly,
+		Count:    3,
+		ByDay:    []WeekdayNum{{N: 1, Day: Monday}, {N: -1, Day: Friday}},
+		BySetPos: []int{1},
+		Extra:    []RecurPart{{Name: "X-NAME", Value: "value"}},
+	}
+	want := "FREQ=MONTHLY;COUNT=3;BYDAY=1MO,-1FR;BYSETPOS=1;X-NAME=value"
+	if got := r.String(); got != want {
+		t.Errorf("Recur.String() = %q, want %q", got, want)
+	}
+}
+
+func TestParseRecurInvalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"COUNT=1",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=FORTNIGHTLY",
+		"FREQ=DAILY;COUNT=2;UNTIL=20240101",
+		"FREQ=DAILY;COUNT=0",
+		"FREQ=DAILY;BYHOUR=24",
+		"FREQ=DAILY;BYMONTH=13",
+		"FREQ=DAILY;BYMONTHDAY=0",
+		"FREQ=MONTHLY;BYDAY=0MO",
+		"FREQ=MONTHLY;BYDAY=XX",
+		"FREQ=DAILY;UNTIL=2024",
+		"FREQ=DAILY;INTERVAL",
+		"FREQ=YEARLY;BYMONTH=5X",
+		"FREQ=MONTHLY;SKIP=OMIT",
+		"RSCALE=HEBREW;FREQ=YEARLY;SKIP=SIDEWAYS",
+	} {
+		if r, err := ParseRecur(s); err == nil {
+			t.Errorf("ParseRecur(%q) = %v, want an error", s, r)
+		}
+	}
+}
+
+func TestRecurCheckStart(t *testing.T) {
+	testCases := []struct {
+		DTStart string
+		TZID    string
+		RRule   string
+		Valid   bool
+	}{
+		{"20240101", "", "FREQ=DAILY;UNTIL=20240110", true},
+		{"20240101", "", "FREQ=DAILY;UNTIL=20240110T000000Z", false},
+		{"20240101T100000Z", "", "FREQ=DAILY;UNTIL=20240110T100000Z", true},
+		{"20240101T100000Z", "", "FREQ=DAILY;UNTIL=20240110", false},
+		{"20240101T100000", "Europe/Paris", "FREQ=DAILY;UNTIL=20240110T090000Z", true},
+		{"20240101T100000", "Europe/Paris", "FREQ=DAILY;UNTIL=20240110T100000", false},
+		{"20240101T100000", "", "FREQ=DAILY;UNTIL=20240110T100000", true},
+		{"20240101T100000", "", "FREQ=DAILY;UNTIL=20240110T100000Z", false},
+	}
+
+	for _, tCase := range testCases {
+		props := make(Props)
+		dtstart := NewProp(PropDateTimeStart)
+		dtstart.Value = tCase.DTStart
+		if len(tCase.DTStart) == len(dateFormat) {
+			dtstart.SetValueType(ValueDate)
+		}
+		if tCase.TZID != "" {
+			dtstart.Params.Set(PropTimezoneID, tCase.TZID)
+		}
+		props.Set(dtstart)
+		rrule := NewProp(PropRecurrenceRule)
+		rrule.Value = tCase.RRule
+		props.Set(rrule)
+
+		r, err := props.Recur()
+		if err != nil {
+			t.Fatalf("Props.Recur() with RRULE %v = %v", tCase.RRule, err)
+		}
+		err = r.CheckStart(dtstart)
+		if tCase.Valid && err != nil {
+			t.Errorf("Recur.CheckStart() with DTSTART %v and RRULE %v = %v", tCase.DTStart, tCase.RRule, err)
+		} else if !tCase.Valid && err == nil {
+			t.Errorf("Recur.CheckStart() with DTSTART %v and RRULE %v = nil, want an error", tCase.DTStart, tCase.RRule)
+		}
+
+		// Expansion tolerates UNTIL values of the wrong type
+		if _, err := time.LoadLocation(tCase.TZID); err != nil {
+			continue
+		}
+		comp := NewComponent(CompEvent)
+		comp.Props = props
+		set, err := comp.RecurrenceSet(nil)
+		if err != nil {
+			t.Errorf("Component.RecurrenceSet() with DTSTART %v and RRULE %v = %v", tCase.DTStart, tCase.RRule, err)
+		} else if n := len(set.All()); n < 9 || n > 10 {
+			t.Errorf("Component.RecurrenceSet() with DTSTART %v and RRULE %v has %v occurrences, want 9 or 10", tCase.DTStart, tCase.RRule, n)
+		}
+	}
+}
+
+func TestRecurROption(t *testing.T) {
+	localTimezone, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := ParseRecur("FREQ=WEEKLY;UNTIL=20240131T100000;BYDAY=MO,2TU;WKST=SU;X-FOO=bar")
+	if err != nil {
+		t.Fatalf("ParseRecur() = %v", err)
+	}
+	roption, err := r.ROption(localTimezone)
+	if err != nil {
+		t.Fatalf("Recur.ROption() = %v", err)
+	}
+
+	want := &rrule.ROption{
+		Freq:      rrule.WEEKLY,
+		Until:     time.Date(2024, time.January, 31, 10, 0, 0, 0, localTimezone),
+		Wkst:      rrule.SU,
+		Byweekday: []rrule.Weekday{rrule.MO, rrule.TU.Nth(2)},
+	}
+	if !reflect.DeepEqual(roption, want) {
+		t.Errorf("Recur.ROption() = %v, want %v", roption, want)
+	}
+
+	r.Extra = append(r.Extra, RecurPart{Name: "UNKNOWN", Value: "1"})
+	if _, err := r.ROption(localTimezone); err == nil {
+		t.Errorf("Recur.ROption() with unknown part = nil, want an error")
+	}
+}
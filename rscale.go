@@ -0,0 +1,335 @@
+package ical
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Fixed day number of 1970-01-01. Fixed day numbers count days in the
+// proleptic Gregorian calendar, 0001-01-01 being day 1.
+const unixEpochFixed = 719163
+
+// Non-Gregorian recurrence rules without COUNT or UNTIL are expanded up to
+// this number of years after DTSTART.
+const rscaleHorizonYears = 100
+
+// Expansion stops after this number of consecutive periods without any
+// occurrence, e.g. for rules which can never match.
+const rscaleMaxEmptyPeriods = 1000
+
+func fixedFromTime(t time.Time) int {
+	y, m, d := t.Date()
+	return int(time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix()/86400) + unixEpochFixed
+}
+
+func dateFromFixed(fixed int) (year int, month time.Month, day int) {
+	return time.Unix(int64(fixed-unixEpochFixed)*86400, 0).UTC().Date()
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+func mod(a, b int) int {
+	return a - b*floorDiv(a, b)
+}
+
+// calendarDate is a date in a calendar system. Leap months share the number
+// of the month they follow, as in RFC 7529.
+type calendarDate struct {
+	Year  int
+	Month RecurMonth
+	Day   int
+}
+
+// calendarSystem converts dates of a calendar system to fixed day numbers.
+type calendarSystem interface {
+	fromFixed(fixed int) calendarDate
+	// monthStart returns the fixed day number of the first day of a month,
+	// which must exist.
+	monthStart(year int, month RecurMonth) int
+	// monthLength returns the number of days in a month, which must exist.
+	monthLength(year int, month RecurMonth) int
+	// hasLeapMonth reports whether a year has a leap month following month.
+	hasLeapMonth(year, month int) bool
+}
+
+// Calendar systems supported for the RSCALE rule part, keyed by their CLDR
+// name.
+var calendarSystems = map[string]calendarSystem{
+	"GREGORIAN": gregorianCalendar{},
+	"HEBREW":    hebrewCalendar{},
+	"CHINESE":   newChineseCalendar(),
+}
+
+// nextMonth returns the month following the specified one.
+func nextMonth(cal calendarSystem, year int, month RecurMonth) (int, RecurMonth) {
+	switch {
+	case !month.Leap && cal.hasLeapMonth(year, month.Month):
+		return year, RecurMonth{Month: month.Month, Leap: true}
+	case month.Month >= 12:
+		return year + 1, RecurMonth{Month: 1}
+	default:
+		return year, RecurMonth{Month: month.Month + 1}
+	}
+}
+
+type gregorianCalendar struct{}
+
+func (gregorianCalendar) fromFixed(fixed int) calendarDate {
+	y, m, d := dateFromFixed(fixed)
+	return calendarDate{Year: y, Month: RecurMonth{Month: int(m)}, Day: d}
+}
+
+func (gregorianCalendar) monthStart(year int, month RecurMonth) int {
+	return fixedFromTime(time.Date(year, time.Month(month.Month), 1, 0, 0, 0, 0, time.UTC))
+}
+
+func (gregorianCalendar) monthLength(year int, month RecurMonth) int {
+	return time.Date(year, time.Month(month.Month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func (gregorianCalendar) hasLeapMonth(year, month int) bool {
+	return false
+}
+
+// needsRScale reports whether the rule depends on a calendar system other
+// than the Gregorian one, or on SKIP semantics. Such rules can't be expanded
+// by rrule-go.
+func (r *Recur) needsRScale() bool {
+	for _, month := range r.ByMonth {
+		if month.Leap {
+			return true
+		}
+	}
+
+	byMonth := r.Freq == FrequencyYearly || r.Freq == FrequencyMonthly
+	if r.Skip != "" && r.Skip != SkipOmit && byMonth {
+		return true
+	}
+	if r.RScale == "" || r.RScale == "GREGORIAN" {
+		return false
+	}
+	return byMonth || len(r.ByMonth) > 0 || len(r.ByMonthDay) > 0 || len(r.ByYearDay) > 0 || len(r.ByWeekNo) > 0
+}
+
+// rscaleIterator expands a recurrence rule in its calendar system, as
+// defined in RFC 7529. Only YEARLY and MONTHLY rules restricted by BYMONTH
+// and BYMONTHDAY are supported.
+type rscaleIterator struct {
+	recur    *Recur
+	cal      calendarSystem
+	dtstart  time.Time
+	until    time.Time
+	months   []RecurMonth
+	days     []int
+	interval int
+
+	// Current period
+	year  int
+	month RecurMonth
+
+	count    int
+	empty    int
+	pending  []time.Time
+	finished bool
+}
+
+func (r *Recur) rscaleIterator(dtstart time.Time) (*rscaleIterator, error) {
+	rscale := r.RScale
+	if rscale == "" {
+		rscale = "GREGORIAN"
+	}
+	cal, ok := calendarSystems[rscale]
+	if !ok {
+		return nil, fmt.Errorf("ical: unsupported recurrence RSCALE: %q", r.RScale)
+	}
+
+	if r.Freq != FrequencyYearly && r.Freq != FrequencyMonthly {
+		return nil, fmt.Errorf("ical: unsupported recurrence frequency with RSCALE=%v: %v", rscale, r.Freq)
+	}
+	if len(r.BySecond) > 0 || len(r.ByMinute) > 0 || len(r.ByHour) > 0 || len(r.ByDay) > 0 || len(r.ByYearDay) > 0 || len(r.ByWeekNo) > 0 || len(r.BySetPos) > 0 {
+		return nil, fmt.Errorf("ical: unsupported recurrence rule parts with RSCALE=%v", rscale)
+	}
+	for _, part := range r.Extra {
+		if !strings.HasPrefix(part.Name, "X-") {
+			return nil, fmt.Errorf("ical: unsupported recurrence rule part: %v", part.Name)
+		}
+	}
+
+	start := cal.fromFixed(fixedFromTime(dtstart))
+	it := &rscaleIterator{
+		recur:    r,
+		cal:      cal,
+		dtstart:  dtstart,
+		until:    r.until(dtstart.Location()),
+		months:   r.ByMonth,
+		days:     r.ByMonthDay,
+		interval: r.Interval,
+		year:     start.Year,
+		month:    start.Month,
+	}
+	if len(it.months) == 0 && r.Freq == FrequencyYearly {
+		it.months = []RecurMonth{start.Month}
+	}
+	if len(it.days) == 0 {
+		it.days = []int{start.Day}
+	}
+	if it.interval <= 0 {
+		it.interval = 1
+	}
+	return it, nil
+}
+
+// next returns the next occurrence, or false if there are none left.
+func (it *rscaleIterator) next() (time.Time, bool) {
+	for len(it.pending) == 0 {
+		if it.finished || it.empty >= rscaleMaxEmptyPeriods {
+			return time.Time{}, false
+		}
+
+		periodStart := it.cal.monthStart(it.year, it.month)
+		if it.recur.Freq == FrequencyYearly {
+			periodStart = it.cal.monthStart(it.year, RecurMonth{Month: 1})
+		}
+		if !it.until.IsZero() && periodStart > fixedFromTime(it.until) {
+			it.finished = true
+			break
+		}
+
+		it.pending = it.expandPeriod()
+		if len(it.pending) == 0 {
+			it.empty++
+		} else {
+			it.empty = 0
+		}
+		it.advance()
+	}
+	if len(it.pending) == 0 {
+		return time.Time{}, false
+	}
+
+	t := it.pending[0]
+	it.pending = it.pending[1:]
+	if (it.recur.Count > 0 && it.count >= it.recur.Count) || (!it.until.IsZero() && t.After(it.until)) {
+		it.finished = true
+		it.pending = nil
+		return time.Time{}, false
+	}
+	it.count++
+	return t, true
+}
+
+func (it *rscaleIterator) advance() {
+	if it.recur.Freq == FrequencyYearly {
+		it.year += it.interval
+		return
+	}
+	for i := 0; i < it.interval; i++ {
+		it.year, it.month = nextMonth(it.cal, it.year, it.month)
+	}
+}
+
+// expandPeriod returns the sorted occurrences in the current period, not
+// before DTSTART.
+func (it *rscaleIterator) expandPeriod() []time.Time {
+	type month struct {
+		year  int
+		month RecurMonth
+	}
+	var months []month
+	if it.recur.Freq == FrequencyYearly {
+		for _, m := range it.months {
+			if y, m, ok := it.resolveMonth(it.year, m); ok {
+				months = append(months, month{y, m})
+			}
+		}
+	} else if len(it.months) == 0 {
+		months = []month{{it.year, it.month}}
+	} else {
+		for _, m := range it.months {
+			if y, m, ok := it.resolveMonth(it.year, m); ok && y == it.year && m == it.month {
+				months = []month{{it.year, it.month}}
+				break
+			}
+		}
+	}
+
+	var l []time.Time
+	for _, m := range months {
+		for _, day := range it.days {
+			fixed, ok := it.resolveDay(m.year, m.month, day)
+			if !ok {
+				continue
+			}
+			y, mo, d := dateFromFixed(fixed)
+			wall := time.Date(y, mo, d, it.dtstart.Hour(), it.dtstart.Minute(), it.dtstart.Second(), 0, time.UTC)
+			t := localTime(wall, it.dtstart.Location())
+			if !t.Before(it.dtstart) {
+				l = append(l, t)
+			}
+		}
+	}
+
+	sort.Slice(l, func(i, j int) bool {
+		return l[i].Before(l[j])
+	})
+	out := l[:0]
+	for i, t := range l {
+		if i == 0 || !t.Equal(l[i-1]) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// resolveMonth applies SKIP to a month which may not exist in a year.
+func (it *rscaleIterator) resolveMonth(year int, month RecurMonth) (int, RecurMonth, bool) {
+	if month.Month < 1 || month.Month > 12 {
+		return 0, RecurMonth{}, false
+	}
+	if !month.Leap || it.cal.hasLeapMonth(year, month.Month) {
+		return year, month, true
+	}
+
+	switch it.recur.Skip {
+	case SkipBackward:
+		return year, RecurMonth{Month: month.Month}, true
+	case SkipForward:
+		y, m := nextMonth(it.cal, year, RecurMonth{Month: month.Month})
+		return y, m, true
+	default:
+		return 0, RecurMonth{}, false
+	}
+}
+
+// resolveDay returns the fixed day number of a day of a month, applying SKIP
+// if the day doesn't exist.
+func (it *rscaleIterator) resolveDay(year int, month RecurMonth, day int) (int, bool) {
+	start := it.cal.monthStart(year, month)
+	n := it.cal.monthLength(year, month)
+	if day < 0 {
+		day = n + day + 1
+	}
+
+	switch {
+	case day >= 1 && day <= n:
+		return start + day - 1, true
+	case it.recur.Skip == SkipBackward && day > n:
+		return start + n - 1, true
+	case it.recur.Skip == SkipBackward:
+		return start - 1, true
+	case it.recur.Skip == SkipForward && day > n:
+		return start + n, true
+	case it.recur.Skip == SkipForward:
+		return start, true
+	default:
+		return 0, false
+	}
+}
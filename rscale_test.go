@@ -0,0 +1,170 @@
+package ical
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHebrewCalendar(t *testing.T) {
+	testCases := []struct {
+		date string
+		want calendarDate
+	}{
+		{"2023-09-16", calendarDate{5784, RecurMonth{Month: 1}, 1}},
+		{"2024-03-24", calendarDate{5784, RecurMonth{Month: 6}, 14}},
+		{"2024-04-23", calendarDate{5784, RecurMonth{Month: 7}, 15}},
+		{"2024-10-03", calendarDate{5785, RecurMonth{Month: 1}, 1}},
+		{"2024-12-26", calendarDate{5785, RecurMonth{Month: 3}, 25}},
+		{"2025-03-14", calendarDate{5785, RecurMonth{Month: 6}, 14}},
+		{"2014-02-08", calendarDate{5774, RecurMonth{Month: 5, Leap: true}, 8}},
+	}
+	cal := hebrewCalendar{}
+	for _, tc := range testCases {
+		t.Run(tc.date, func(t *testing.T) {
+			date, err := time.Parse("2006-01-02", tc.date)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fixed := fixedFromTime(date)
+			if got := cal.fromFixed(fixed); got != tc.want {
+				t.Errorf("fromFixed() = %v, want %v", got, tc.want)
+			}
+			if got := cal.monthStart(tc.want.Year, tc.want.Month) + tc.want.Day - 1; got != fixed {
+				t.Errorf("monthStart() + day = %v, want %v", got, fixed)
+			}
+		})
+	}
+}
+
+func TestChineseCalendar(t *testing.T) {
+	// New year and leap month start dates
+	testCases := []struct {
+		year      int
+		newYear   string
+		leapMonth int
+		leapStart string
+	}{
+		{2017, "2017-01-28", 6, "2017-07-23"},
+		{2019, "2019-02-05", 0, ""},
+		{2020, "2020-01-25", 4, "2020-05-23"},
+		{2023, "2023-01-22", 2, "2023-03-22"},
+		{2024, "2024-02-10", 0, ""},
+		{2025, "2025-01-29", 6, "2025-07-25"},
+		{2033, "2033-01-31", 11, "2033-12-22"},
+	}
+	cal := newChineseCalendar()
+	for _, tc := range testCases {
+		t.Run(tc.newYear, func(t *testing.T) {
+			newYear, err := time.Parse("2006-01-02", tc.newYear)
+			if err != nil {
+				t.Fatal(err)
+			}
+			date := cal.fromFixed(fixedFromTime(newYear))
+			if date.Month != (RecurMonth{Month: 1}) || date.Day != 1 {
+				t.Errorf("fromFixed() = %v, want the first day of the year", date)
+			}
+
+			for month := 1; month <= 12; month++ {
+				if got := cal.hasLeapMonth(date.Year, month); got != (month == tc.leapMonth) {
+					t.Errorf("hasLeapMonth(%v) = %v", month, got)
+				}
+			}
+			if tc.leapMonth == 0 {
+				return
+			}
+			y, m, d := dateFromFixed(cal.monthStart(date.Year, RecurMonth{Month: tc.leapMonth, Leap: true}))
+			if got := time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Format("2006-01-02"); got != tc.leapStart {
+				t.Errorf("monthStart() = %v, want %v", got, tc.leapStart)
+			}
+		})
+	}
+}
+
+func TestRecurrenceSetRScale(t *testing.T) {
+	testCases := []struct {
+		name    string
+		dtstart string
+		rrule   string
+		want    []string
+	}{
+		{
+			name:    "chinese-new-year",
+			dtstart: "20130210",
+			rrule:   "RSCALE=CHINESE;FREQ=YEARLY;COUNT=4",
+			want:    []string{"20130210", "20140131", "20150219", "20160208"},
+		},
+		{
+			name:    "hebrew-leap-month-omit",
+			dtstart: "20140208",
+			rrule:   "RSCALE=HEBREW;FREQ=YEARLY;BYMONTH=5L;BYMONTHDAY=8;COUNT=3",
+			want:    []string{"20140208", "20160217", "20190213"},
+		},
+		{
+			name:    "hebrew-leap-month-backward",
+			dtstart: "20140208",
+			rrule:   "RSCALE=HEBREW;FREQ=YEARLY;BYMONTH=5L;BYMONTHDAY=8;SKIP=BACKWARD;COUNT=3",
+			want:    []string{"20140208", "20150128", "20160217"},
+		},
+		{
+			name:    "hebrew-leap-month-forward",
+			dtstart: "20140208",
+			rrule:   "RSCALE=HEBREW;FREQ=YEARLY;BYMONTH=5L;BYMONTHDAY=8;SKIP=FORWARD;UNTIL=20180223",
+			want:    []string{"20140208", "20150227", "20160217", "20170306", "20180223"},
+		},
+		{
+			name:    "chinese-monthly",
+			dtstart: "20230322",
+			rrule:   "RSCALE=CHINESE;FREQ=MONTHLY;COUNT=3",
+			want:    []string{"20230322", "20230420", "20230519"},
+		},
+		{
+			name:    "gregorian-backward",
+			dtstart: "20150131",
+			rrule:   "RSCALE=GREGORIAN;FREQ=MONTHLY;SKIP=BACKWARD;COUNT=4",
+			want:    []string{"20150131", "20150228", "20150331", "20150430"},
+		},
+		{
+			name:    "gregorian-forward",
+			dtstart: "20150131",
+			rrule:   "RSCALE=GREGORIAN;FREQ=MONTHLY;SKIP=FORWARD;COUNT=4",
+			want:    []string{"20150131", "20150301", "20150331", "20150501"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			event := NewEvent()
+			event.Props.SetDate(PropDateTimeStart, mustParseDate(t, tc.dtstart))
+			event.Props.Set(&Prop{Name: PropRecurrenceRule, Params: make(Params), Value: tc.rrule})
+
+			set, err := event.RecurrenceSet(time.UTC)
+			if err != nil {
+				t.Fatalf("RecurrenceSet() = %v", err)
+			}
+			var got []string
+			for _, occurrence := range set.All() {
+				got = append(got, occurrence.Format(dateFormat))
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("RecurrenceSet().All() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRecurrenceSetRScaleUnsupported(t *testing.T) {
+	event := NewEvent()
+	event.Props.SetDate(PropDateTimeStart, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	event.Props.Set(&Prop{Name: PropRecurrenceRule, Params: make(Params), Value: "RSCALE=ETHIOPIC;FREQ=YEARLY"})
+	if _, err := event.RecurrenceSet(time.UTC); err == nil {
+		t.Errorf("RecurrenceSet() = nil, want an error")
+	}
+}
+
+func mustParseDate(t *testing.T, s string) time.Time {
+	date, err := time.Parse(dateFormat, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return date
+}
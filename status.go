@@ -0,0 +1,121 @@
+package ical
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Class returns the class of the status code, i.e. its first number:
+//
+//	1: preliminary success
+//	2: successful
+//	3: client error
+//	4: scheduling error
+//	5: service error
+//
+// Zero is returned if the status code is malformed.
+func (code RequestStatusCode) Class() int {
+	s := string(code)
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		s = s[:i]
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// Description returns the description of a status code defined in RFC 5546,
+// or an empty string if the status code is unknown.
+func (code RequestStatusCode) Description() string {
+	return requestStatusDescriptions[code]
+}
+
+// checkRequestStatusCode checks the status code syntax:
+//
+//	statcode = 1*DIGIT 1*2("." 1*DIGIT)
+func checkRequestStatusCode(code RequestStatusCode) error {
+	parts := strings.Split(string(code), ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return fmt.Errorf("ical: invalid request status code: %q", code)
+	}
+	for _, part := range parts {
+		if _, err := strconv.ParseUint(part, 10, 32); err != nil {
+			return fmt.Errorf("ical: invalid request status code: %q", code)
+		}
+	}
+	return nil
+}
+
+// RequestStatus is the status code returned for a scheduling request,
+// defined in RFC 5545 section 3.8.8.3.
+type RequestStatus struct {
+	Code        RequestStatusCode
+	Description string
+	// ExtraData is optional exception data, e.g. the offending property.
+	ExtraData string
+}
+
+// RequestStatus parses the property value as a request status.
+func (prop *Prop) RequestStatus() (*RequestStatus, error) {
+	if err := prop.expectValueType(ValueText); err != nil {
+		return nil, err
+	}
+
+	parts, err := splitText(prop.Value, ';')
+	if err != nil {
+		return nil, err
+	}
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, fmt.Errorf("ical: malformed request status: expected 2 or 3 fields, got %v", len(parts))
+	}
+
+	status := &RequestStatus{
+		Code:        RequestStatusCode(parts[0]),
+		Description: parts[1],
+	}
+	if err := checkRequestStatusCode(status.Code); err != nil {
+		return nil, err
+	}
+	if len(parts) == 3 {
+		status.ExtraData = parts[2]
+	}
+	return status, nil
+}
+
+func (prop *Prop) SetRequestStatus(status *RequestStatus) {
+	prop.SetValueType(ValueText)
+
+	var sb strings.Builder
+	sb.WriteString(string(status.Code))
+	sb.WriteByte(';')
+	writeEscapedText(&sb, status.Description)
+	if status.ExtraData != "" {
+		sb.WriteByte(';')
+		writeEscapedText(&sb, status.ExtraData)
+	}
+	prop.Value = sb.String()
+}
+
+// RequestStatuses returns the list of REQUEST-STATUS properties.
+func (props Props) RequestStatuses() ([]RequestStatus, error) {
+	l := props.Values(PropRequestStatus)
+	statuses := make([]RequestStatus, 0, len(l))
+	for i := range l {
+		status, err := l[i].RequestStatus()
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, *status)
+	}
+	return statuses, nil
+}
+
+// AddRequestStatus appends a REQUEST-STATUS property.
+func (props Props) AddRequestStatus(status *RequestStatus) {
+	prop := NewProp(PropRequestStatus)
+	prop.SetRequestStatus(status)
+	props.Add(prop)
+}
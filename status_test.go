@@ -0,0 +1,84 @@
+package ical
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRequestStatus(t *testing.T) {
+	testCases := []struct {
+		Value    string
+		Expected *RequestStatus
+	}{
+		{
+			Value: "2.0;Success",
+			Expected: &RequestStatus{
+				Code:        RequestStatusSuccess,
+				Description: "Success",
+			},
+		},
+		{
+			Value: `3.7;Invalid calendar user;ATTENDEE:mailto:jsmith@example.com`,
+			Expected: &RequestStatus{
+				Code:        RequestStatusInvalidCalendarUser,
+				Description: "Invalid calendar user",
+				ExtraData:   "ATTENDEE:mailto:jsmith@example.com",
+			},
+		},
+		{
+			Value: `3.1;Invalid property value;DTSTART:96-Apr-01`,
+			Expected: &RequestStatus{
+				Code:        RequestStatusInvalidPropertyValue,
+				Description: "Invalid property value",
+				ExtraData:   "DTSTART:96-Apr-01",
+			},
+		},
+		{
+			Value: `4.1;Event conflict\; please\, reschedule`,
+			Expected: &RequestStatus{
+				Code:        "4.1",
+				Description: "Event conflict; please, reschedule",
+			},
+		},
+	}
+
+	for _, tCase := range testCases {
+		t.Run(tCase.Value, func(t *testing.T) {
+			prop := NewProp(PropRequestStatus)
+			prop.Value = tCase.Value
+			status, err := prop.RequestStatus()
+			if err != nil {
+				t.Fatalf("Prop.RequestStatus() = %v", err)
+			}
+			if !reflect.DeepEqual(status, tCase.Expected) {
+				t.Errorf("Prop.RequestStatus() = %#v, want %#v", status, tCase.Expected)
+			}
+
+			prop = NewProp(PropRequestStatus)
+			prop.SetRequestStatus(tCase.Expected)
+			if prop.Value != tCase.Value {
+				t.Errorf("Prop.SetRequestStatus() = %q, want %q", prop.Value, tCase.Value)
+			}
+		})
+	}
+
+	for _, v := range []string{"2.0", "2;Success", "2.x;Success", "2.0.0.0;Success"} {
+		prop := NewProp(PropRequestStatus)
+		prop.Value = v
+		if _, err := prop.RequestStatus(); err == nil {
+			t.Errorf("Prop.RequestStatus(%q) = nil, want an error", v)
+		}
+	}
+}
+
+func TestRequestStatusCode(t *testing.T) {
+	if got := RequestStatusNoSchedulingSupport.Class(); got != 5 {
+		t.Errorf("RequestStatusCode.Class() = %v, want 5", got)
+	}
+	if got, want := RequestStatusInvalidCalendarUser.Description(), "Invalid Calendar User"; got != want {
+		t.Errorf("RequestStatusCode.Description() = %q, want %q", got, want)
+	}
+	if got := RequestStatusCode("3.99").Description(); got != "" {
+		t.Errorf("RequestStatusCode.Description() = %q, want empty", got)
+	}
+}
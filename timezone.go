@@ -0,0 +1,1327 @@
+package ical
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/teambition/rrule-go"
+)
+
+// Onsets of VTIMEZONE observances with an unbounded recurrence rule are
+// computed up to the end of this year.
+const timezoneMaxYear = 2200
+
+// observance is a STANDARD or DAYLIGHT sub-component of a VTIMEZONE
+// component, defined in RFC 5545 section 3.6.5.
+type observance struct {
+	comp       *Component
+	name       string
+	dst        bool
+	offsetFrom time.Duration
+	offsetTo   time.Duration
+	// Wall-clock value of DTSTART in UTC, expressed in offsetFrom
+	start time.Time
+}
+
+func parseObservance(comp *Component) (*observance, error) {
+	obs := &observance{comp: comp, dst: comp.Name == CompTimezoneDaylight}
+
+	var err error
+	if obs.name, err = comp.Props.Text(PropTimezoneName); err != nil {
+		return nil, err
+	}
+	if comp.Props.Get(PropTimezoneOffsetFrom) == nil || comp.Props.Get(PropTimezoneOffsetTo) == nil {
+		return nil, fmt.Errorf("ical: missing TZOFFSETFROM or TZOFFSETTO in %v", comp.Name)
+	}
+	if obs.offsetFrom, err = comp.Props.UTCOffset(PropTimezoneOffsetFrom); err != nil {
+		return nil, err
+	}
+	if obs.offsetTo, err = comp.Props.UTCOffset(PropTimezoneOffsetTo); err != nil {
+		return nil, err
+	}
+
+	dt, err := comp.Props.DateTimeValue(PropDateTimeStart)
+	if err != nil {
+		return nil, err
+	} else if dt.IsZero() {
+		return nil, fmt.Errorf("ical: missing DTSTART in %v", comp.Name)
+	}
+	obs.start = dt.Wall
+
+	return obs, nil
+}
+
+// onsets returns the wall-clock onsets of the observance in UTC, expressed in
+// offsetFrom, up to end (exclusive).
+func (obs *observance) onsets(end time.Time) ([]time.Time, error) {
+	var l []time.Time
+	if obs.start.Before(end) {
+		l = append(l, obs.start)
+	}
+
+	ruleOnsets, err := obs.ruleOnsets(end)
+	if err != nil {
+		return nil, err
+	}
+	l = append(l, ruleOnsets...)
+
+	rdates, err := obs.rdates()
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range rdates {
+		if t.Before(end) {
+			l = append(l, t)
+		}
+	}
+
+	return l, nil
+}
+
+// ruleOnsets returns the wall-clock onsets generated by the RRULE property of
+// the observance, up to end (exclusive).
+func (obs *observance) ruleOnsets(end time.Time) ([]time.Time, error) {
+	prop := obs.comp.Props.Get(PropRecurrenceRule)
+	if prop == nil {
+		return nil, nil
+	}
+
+	recur, err := prop.Recur()
+	if err != nil {
+		return nil, err
+	}
+	roption, err := recur.ROption(time.UTC)
+	if err != nil {
+		return nil, err
+	}
+	if !recur.UntilDate && !recur.UntilFloating && !recur.Until.IsZero() {
+		// UNTIL is in UTC, onsets are wall-clock values
+		roption.Until = recur.Until.Add(obs.offsetFrom)
+	}
+
+	// rrule-go can't expand further than the maximum time.Duration after
+	// DTSTART. Outlook uses DTSTART values in 1601, so unbounded yearly
+	// rules are expanded in windows starting on the same day.
+	var windowYears int
+	if roption.Freq == rrule.YEARLY && roption.Count == 0 {
+		interval := roption.Interval
+		if interval <= 0 {
+			interval = 1
+		}
+		windowYears = interval * (200/interval + 1)
+	}
+
+	var l []time.Time
+	for start := obs.start; start.Before(end); start = start.AddDate(windowYears, 0, 0) {
+		roption.Dtstart = start
+		rule, err := rrule.NewRRule(*roption)
+		if err != nil {
+			return nil, fmt.Errorf("ical: error buildling rrule: %v", err)
+		}
+
+		windowEnd := end
+		if windowYears > 0 {
+			windowEnd = start.AddDate(windowYears, 0, 0)
+			if windowEnd.After(end) {
+				windowEnd = end
+			}
+		}
+		l = append(l, rule.Between(start, windowEnd, true)...)
+
+		if windowYears == 0 {
+			break
+		}
+	}
+	return l, nil
+}
+
+// rdates returns the wall-clock onsets listed in the RDATE properties of the
+// observance.
+func (obs *observance) rdates() ([]time.Time, error) {
+	var l []time.Time
+	for _, prop := range obs.comp.Props.Values(PropRecurrenceDates) {
+		rdates, err := prop.DateTimeList(time.UTC)
+		if err != nil {
+			return nil, err
+		}
+		l = append(l, rdates...)
+	}
+	return l, nil
+}
+
+// timezoneTransition is the instant an observance comes into effect.
+type timezoneTransition struct {
+	at  time.Time
+	obs *observance
+}
+
+// timezoneTransitions returns the sorted list of transitions of a VTIMEZONE
+// component, up to end (exclusive).
+func timezoneTransitions(comp *Component, end time.Time) ([]timezoneTransition, error) {
+	var l []timezoneTransition
+	for _, child := range comp.Children {
+		if child.Name != CompTimezoneStandard && child.Name != CompTimezoneDaylight {
+			continue
+		}
+
+		obs, err := parseObservance(child)
+		if err != nil {
+			return nil, err
+		}
+		onsets, err := obs.onsets(end.Add(obs.offsetFrom))
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range onsets {
+			l = append(l, timezoneTransition{at: t.Add(-obs.offsetFrom), obs: obs})
+		}
+	}
+	if len(l) == 0 {
+		return nil, fmt.Errorf("ical: VTIMEZONE has no STANDARD or DAYLIGHT observance")
+	}
+
+	sort.SliceStable(l, func(i, j int) bool {
+		return l[i].at.Before(l[j].at)
+	})
+	out := l[:1]
+	for _, tr := range l[1:] {
+		if !tr.at.Equal(out[len(out)-1].at) {
+			out = append(out, tr)
+		}
+	}
+	return out, nil
+}
+
+// timezoneZone is a local time type: an offset, an abbreviation and whether
+// daylight saving time is in effect.
+type timezoneZone struct {
+	offset time.Duration
+	name   string
+	dst    bool
+}
+
+func (zone timezoneZone) abbreviation() string {
+	if zone.name != "" {
+		return zone.name
+	}
+	offset := zone.offset
+	sign := '+'
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+	return fmt.Sprintf("%c%02d%02d", sign, int(offset.Hours()), int(offset.Minutes())%60)
+}
+
+// timezoneLocation converts a VTIMEZONE component to a location.
+func timezoneLocation(comp *Component) (*time.Location, error) {
+	tzid, err := comp.Props.Text(PropTimezoneID)
+	if err != nil {
+		return nil, err
+	} else if tzid == "" {
+		return nil, fmt.Errorf("ical: missing TZID in VTIMEZONE")
+	}
+
+	end := time.Date(timezoneMaxYear+1, 1, 1, 0, 0, 0, 0, time.UTC)
+	transitions, err := timezoneTransitions(comp, end)
+	if err != nil {
+		return nil, err
+	}
+
+	// The first zone applies before the first transition. Prefer the name
+	// of an observance with a matching offset.
+	first := transitions[0].obs
+	initial := timezoneZone{offset: first.offsetFrom}
+	for _, tr := range transitions {
+		if tr.obs.offsetTo == first.offsetFrom {
+			initial.name = tr.obs.name
+			initial.dst = tr.obs.dst
+			break
+		}
+	}
+
+	loc, err := time.LoadLocationFromTZData(tzid, buildTZif(initial, transitions))
+	if err != nil {
+		return nil, fmt.Errorf("ical: failed to load VTIMEZONE %q: %v", tzid, err)
+	}
+	return loc, nil
+}
+
+// buildTZif encodes transitions in the TZif format, defined in RFC 8536. Only
+// the version 2 data block is populated.
+func buildTZif(initial timezoneZone, transitions []timezoneTransition) []byte {
+	// Zone 0 is only used before the first transition
+	zones := []timezoneZone{initial}
+	zoneIndices := make(map[timezoneZone]int)
+	indices := make([]byte, len(transitions))
+	for i, tr := range transitions {
+		zone := timezoneZone{offset: tr.obs.offsetTo, name: tr.obs.name, dst: tr.obs.dst}
+		index, ok := zoneIndices[zone]
+		if !ok {
+			index = len(zones)
+			zones = append(zones, zone)
+			zoneIndices[zone] = index
+		}
+		indices[i] = byte(index)
+	}
+
+	var chars bytes.Buffer
+	abbrIndices := make([]int, len(zones))
+	for i, zone := range zones {
+		abbrIndices[i] = chars.Len()
+		chars.WriteString(zone.abbreviation())
+		chars.WriteByte(0)
+	}
+
+	var b bytes.Buffer
+	writeHeader := func(timeCount, typeCount, charCount int) {
+		b.WriteString("TZif2")
+		b.Write(make([]byte, 15))
+		// isutcnt, isstdcnt, leapcnt, timecnt, typecnt, charcnt
+		for _, n := range []int{0, 0, 0, timeCount, typeCount, charCount} {
+			binary.Write(&b, binary.BigEndian, uint32(n))
+		}
+	}
+
+	// Empty version 1 data block
+	writeHeader(0, 0, 0)
+
+	writeHeader(len(transitions), len(zones), chars.Len())
+	for _, tr := range transitions {
+		binary.Write(&b, binary.BigEndian, tr.at.Unix())
+	}
+	b.Write(indices)
+	for i, zone := range zones {
+		binary.Write(&b, binary.BigEndian, int32(zone.offset/time.Second))
+		if zone.dst {
+			b.WriteByte(1)
+		} else {
+			b.WriteByte(0)
+		}
+		b.WriteByte(byte(abbrIndices[i]))
+	}
+	b.Write(chars.Bytes())
+	// Empty footer
+	b.WriteString("\n\n")
+
+	return b.Bytes()
+}
+
+// timezoneCache contains the locations built from VTIMEZONE components, since
+// building one expands the observances up to timezoneMaxYear. Locations are
+// keyed by the content of the component, so that the cache is shared by all
+// calendars and stays valid if a component is modified.
+var timezoneCache = struct {
+	sync.Mutex
+	locs map[string]*time.Location
+}{locs: make(map[string]*time.Location)}
+
+// timezoneCacheSize is the maximum number of locations in timezoneCache.
+const timezoneCacheSize = 256
+
+// cachedTimezoneLocation is like timezoneLocation, but caches the result.
+func cachedTimezoneLocation(comp *Component) (*time.Location, error) {
+	var sb strings.Builder
+	writeComponentKey(&sb, comp)
+	key := sb.String()
+
+	timezoneCache.Lock()
+	loc, ok := timezoneCache.locs[key]
+	timezoneCache.Unlock()
+	if ok {
+		return loc, nil
+	}
+
+	loc, err := timezoneLocation(comp)
+	if err != nil {
+		return nil, err
+	}
+
+	timezoneCache.Lock()
+	if len(timezoneCache.locs) >= timezoneCacheSize {
+		timezoneCache.locs = make(map[string]*time.Location)
+	}
+	timezoneCache.locs[key] = loc
+	timezoneCache.Unlock()
+	return loc, nil
+}
+
+// writeComponentKey writes a string identifying the content of a component.
+// Control characters, which can't appear in property values, are used as
+// separators.
+func writeComponentKey(sb *strings.Builder, comp *Component) {
+	sb.WriteString(comp.Name)
+	sb.WriteByte(0)
+
+	names := make([]string, 0, len(comp.Props))
+	for name := range comp.Props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, prop := range comp.Props[name] {
+			sb.WriteString(name)
+			params := make([]string, 0, len(prop.Params))
+			for param, values := range prop.Params {
+				params = append(params, param+"\x01"+strings.Join(values, "\x02"))
+			}
+			sort.Strings(params)
+			for _, param := range params {
+				sb.WriteByte(3)
+				sb.WriteString(param)
+			}
+			sb.WriteByte(4)
+			sb.WriteString(prop.Value)
+			sb.WriteByte(0)
+		}
+	}
+
+	for _, child := range comp.Children {
+		writeComponentKey(sb, child)
+	}
+	sb.WriteString("END")
+	sb.WriteByte(0)
+}
+
+// LoadLocation returns the location described by a TZID. VTIMEZONE
+// components of the calendar take precedence over DefaultTimezoneResolver.
+func (cal *Calendar) LoadLocation(tzid string) (*time.Location, error) {
+	return cal.loadLocation(tzid, nil)
+}
+
+// Resolver returns a TimezoneResolver for the calendar: VTIMEZONE components
+// of the calendar take precedence over fallback. A nil fallback uses
+// DefaultTimezoneResolver, like Calendar.LoadLocation.
+func (cal *Calendar) Resolver(fallback TimezoneResolver) TimezoneResolver {
+	return TimezoneResolverFunc(func(tzid string) (*time.Location, error) {
+		return cal.loadLocation(tzid, fallback)
+	})
+}
+
+func (cal *Calendar) loadLocation(tzid string, fallback TimezoneResolver) (*time.Location, error) {
+	for _, child := range cal.Children {
+		if child.Name != CompTimezone {
+			continue
+		}
+		if id, err := child.Props.Text(PropTimezoneID); err == nil && id == tzid {
+			return cachedTimezoneLocation(child)
+		}
+	}
+	return resolverLoader(fallback).load(tzid)
+}
+
+// Locations are scanned for transitions with this step. Time zones don't
+// change more than once in this interval.
+const timezoneScanStep = 6 * time.Hour
+
+// zoneTransition is a change of offset or abbreviation of a location.
+type zoneTransition struct {
+	at         time.Time
+	offsetFrom time.Duration
+	offsetTo   time.Duration
+	name       string
+	dst        bool
+}
+
+// wall returns the wall-clock value of the transition in UTC, expressed in
+// offsetFrom.
+func (tr *zoneTransition) wall() time.Time {
+	return tr.at.Add(tr.offsetFrom)
+}
+
+// zoneTransitions returns the transitions of loc between from (exclusive)
+// and to (inclusive).
+func zoneTransitions(loc *time.Location, from, to time.Time) []zoneTransition {
+	var l []zoneTransition
+	t := from.In(loc)
+	name, offset := t.Zone()
+	for t.Before(to) {
+		next := t.Add(timezoneScanStep)
+		if next.After(to) {
+			next = to.In(loc)
+		}
+		nextName, nextOffset := next.Zone()
+		if nextName != name || nextOffset != offset {
+			// Find the exact second of the transition
+			lo, hi := t.Unix(), next.Unix()
+			for hi-lo > 1 {
+				mid := lo + (hi-lo)/2
+				if midName, midOffset := time.Unix(mid, 0).In(loc).Zone(); midName == name && midOffset == offset {
+					lo = mid
+				} else {
+					hi = mid
+				}
+			}
+			l = append(l, zoneTransition{
+				at:         time.Unix(hi, 0).UTC(),
+				offsetFrom: time.Duration(offset) * time.Second,
+				offsetTo:   time.Duration(nextOffset) * time.Second,
+				name:       nextName,
+			})
+			name, offset = nextName, nextOffset
+		}
+		t = next
+	}
+
+	// Daylight saving time is a temporary increase of the offset
+	for i := range l {
+		tr := &l[i]
+		tr.dst = tr.offsetTo > tr.offsetFrom && i+1 < len(l) && l[i+1].offsetTo < l[i+1].offsetFrom
+	}
+
+	return l
+}
+
+// localTime returns the instant of a wall clock time, expressed in UTC, in
+// loc. As specified in RFC 5545 section 3.3.5, a time in a gap is
+// interpreted with the offset before the gap, and a time in an overlap
+// refers to its first occurrence.
+func localTime(wall time.Time, loc *time.Location) time.Time {
+	if loc == time.UTC {
+		return wall
+	}
+
+	offsetAt := func(t time.Time) time.Duration {
+		_, offset := t.In(loc).Zone()
+		return time.Duration(offset) * time.Second
+	}
+
+	// Transitions are assumed to be more than a day apart
+	before := offsetAt(wall.Add(-24 * time.Hour))
+	after := offsetAt(wall.Add(24 * time.Hour))
+	t := wall.Add(-before)
+	if before != after && offsetAt(t) != before {
+		if t2 := wall.Add(-after); offsetAt(t2) == after {
+			t = t2
+		}
+	}
+	return t.In(loc)
+}
+
+// zonedRule is a recurrence rule starting at a wall clock time, expressed in
+// UTC, in a location. rrule-go resolves local times in a gap or an overlap
+// like time.Date, so the rule is evaluated on wall clock times, and each
+// occurrence is converted to the instant defined by RFC 5545 section 3.3.5.
+type zonedRule struct {
+	roption rrule.ROption
+	wall    time.Time
+	loc     *time.Location
+}
+
+// iterator returns the occurrences of the rule starting at or after from, in
+// chronological order. Occurrences are only converted when requested. Unless
+// the rule has a COUNT, it is evaluated from the period containing from
+// rather than from DTSTART.
+func (r *zonedRule) iterator(from time.Time) (func() (time.Time, bool), error) {
+	option := explicitROption(r.roption, r.wall)
+	until := r.roption.Until
+	if !until.IsZero() {
+		// Occurrences with a later wall clock time may still precede UNTIL
+		// in an overlap
+		option.Until = wallClock(until.In(r.loc)).Add(24 * time.Hour)
+	}
+	if !from.IsZero() && option.Count == 0 {
+		// Occurrences in a gap are moved forward by less than a day
+		option = seekROption(option, wallClock(from.In(r.loc)).Add(-24*time.Hour))
+	}
+	rule, err := rrule.NewRRule(option)
+	if err != nil {
+		return nil, err
+	}
+	walls := rule.Iterator()
+
+	// Occurrences in a gap are moved after the end of the gap, and may
+	// follow occurrences with a later wall clock time
+	var (
+		gap     []time.Time
+		next    time.Time
+		hasNext bool
+		last    time.Time
+	)
+	return func() (time.Time, bool) {
+		for {
+			for !hasNext && walls != nil {
+				w, ok := walls()
+				if !ok {
+					walls = nil
+					break
+				}
+				t := localTime(w, r.loc)
+				if wallClock(t).Equal(w) {
+					next, hasNext = t, true
+				} else {
+					gap = append(gap, t)
+				}
+			}
+
+			var t time.Time
+			switch {
+			case len(gap) > 0 && (!hasNext || gap[0].Before(next)):
+				t, gap = gap[0], gap[1:]
+			case hasNext:
+				t, hasNext = next, false
+			default:
+				return time.Time{}, false
+			}
+
+			if t.Before(from) || (!until.IsZero() && t.After(until)) {
+				continue
+			}
+			if !last.IsZero() && !t.After(last) {
+				continue
+			}
+			last = t
+			return t, true
+		}
+	}, nil
+}
+
+// addTo adds the rule to a recurrence set. The rule of the set is evaluated
+// by rrule-go, so the occurrences it resolves differently are replaced with
+// recurrence and exception dates. This requires expanding the whole rule, so
+// it's only done for rules with a COUNT or an UNTIL: the occurrences of other
+// rules are left as computed by rrule-go, apart from DTSTART.
+func (r *zonedRule) addTo(set *rrule.Set) error {
+	dtstart := localTime(r.wall, r.loc)
+	option := r.roption
+	inGap := !wallClock(dtstart).Equal(r.wall)
+	if inGap {
+		// rrule-go would use the time of the day of the adjusted DTSTART
+		option = explicitROption(option, r.wall)
+	}
+	option.Dtstart = dtstart
+	rule, err := rrule.NewRRule(option)
+	if err != nil {
+		return err
+	}
+	set.RRule(rule)
+
+	if r.loc == time.UTC {
+		return nil
+	} else if option.Count == 0 && option.Until.IsZero() {
+		if inGap {
+			// rrule-go skips DTSTART, since it resolves it to an earlier
+			// instant
+			set.RDate(dtstart)
+		}
+		return nil
+	}
+
+	next, err := r.iterator(time.Time{})
+	if err != nil {
+		return err
+	}
+	// want records whether each occurrence is computed by rrule-go
+	want := make(map[int64]bool)
+	for t, ok := next(); ok; t, ok = next() {
+		want[t.Unix()] = false
+	}
+	got := rule.Iterator()
+	for t, ok := got(); ok; t, ok = got() {
+		if _, ok := want[t.Unix()]; ok {
+			want[t.Unix()] = true
+		} else {
+			set.ExDate(t)
+		}
+	}
+	var rdates []int64
+	for t, found := range want {
+		if !found {
+			rdates = append(rdates, t)
+		}
+	}
+	sort.Slice(rdates, func(i, j int) bool {
+		return rdates[i] < rdates[j]
+	})
+	for _, t := range rdates {
+		set.RDate(time.Unix(t, 0).In(r.loc))
+	}
+	return nil
+}
+
+// zoneRuleKey identifies transitions which can be described by the same
+// yearly recurrence rule.
+type zoneRuleKey struct {
+	offsetFrom time.Duration
+	offsetTo   time.Duration
+	name       string
+	dst        bool
+	month      time.Month
+	weekday    time.Weekday
+	clock      time.Duration
+}
+
+func newZoneRuleKey(tr *zoneTransition) zoneRuleKey {
+	wall := tr.wall()
+	y, m, d := wall.Date()
+	return zoneRuleKey{
+		offsetFrom: tr.offsetFrom,
+		offsetTo:   tr.offsetTo,
+		name:       tr.name,
+		dst:        tr.dst,
+		month:      m,
+		weekday:    wall.Weekday(),
+		clock:      wall.Sub(time.Date(y, m, d, 0, 0, 0, 0, time.UTC)),
+	}
+}
+
+// zoneOrdinal returns the ordinal of the weekday of a transition within its
+// month, counted from the start and from the end of the month.
+func zoneOrdinal(tr *zoneTransition) (n int, last bool) {
+	wall := tr.wall()
+	y, m, d := wall.Date()
+	daysInMonth := time.Date(y, m+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	return (d-1)/7 + 1, d+7 > daysInMonth
+}
+
+// zoneRun is a list of transitions on consecutive years following the same
+// yearly rule.
+type zoneRun struct {
+	transitions []*zoneTransition
+	// Ordinal of the weekday, zero if it varies
+	n int
+	// Whether the weekday is the last one of the month
+	last bool
+	// Whether the rule still applies after the last transition
+	open bool
+}
+
+func (run *zoneRun) extend(tr *zoneTransition) bool {
+	prev := run.transitions[len(run.transitions)-1]
+	if tr.wall().Year() != prev.wall().Year()+1 {
+		return false
+	}
+	n, last := zoneOrdinal(tr)
+	if n != run.n {
+		n = 0
+	}
+	last = last && run.last
+	if n == 0 && !last {
+		return false
+	}
+	run.transitions = append(run.transitions, tr)
+	run.n, run.last = n, last
+	return true
+}
+
+// NewTimezone creates a VTIMEZONE component describing loc between from and
+// to. Transitions following the same rule on consecutive years are described
+// with a recurrence rule, others with recurrence dates. A rule which still
+// applies after to has no end. The observance in effect at from begins at
+// the previous transition, or on the day of from if there is none in the
+// preceding year.
+func NewTimezone(loc *time.Location, from, to time.Time) *Component {
+	tz := NewComponent(CompTimezone)
+	tz.Props.SetText(PropTimezoneID, loc.String())
+
+	// Transitions after to are used to find out whether rules still apply.
+	// Two more years are scanned, since the kind of the last transition is
+	// unknown. The year before from is scanned for the transition in effect.
+	all := zoneTransitions(loc, from.AddDate(-1, 0, 0), to.AddDate(2, 0, 0))
+	first := 0
+	for first < len(all) && !all[first].at.After(from) {
+		first++
+	}
+	if first > 0 {
+		all = all[first-1:]
+	} else {
+		// Without a transition in effect, from is described by a fixed
+		// observance
+		name, offset := from.In(loc).Zone()
+		obs := newObservance(false, time.Duration(offset)*time.Second, time.Duration(offset)*time.Second, name)
+		y, m, d := from.In(loc).Date()
+		obs.Props.SetDateTimeValue(PropDateTimeStart, NewFloatingDateTime(time.Date(y, m, d, 0, 0, 0, 0, time.UTC)))
+		tz.Children = append(tz.Children, obs)
+	}
+
+	var transitions []zoneTransition
+	for _, tr := range all {
+		if tr.at.After(to) {
+			break
+		}
+		transitions = append(transitions, tr)
+	}
+	if len(transitions) == 0 {
+		return tz
+	}
+
+	var runs []*zoneRun
+	open := make(map[zoneRuleKey]*zoneRun)
+	for i := range all {
+		tr := &all[i]
+		key := newZoneRuleKey(tr)
+		run := open[key]
+		if i >= len(transitions) {
+			if run != nil && run.extend(tr) {
+				run.open = true
+			}
+			continue
+		}
+		if run == nil || !run.extend(tr) {
+			n, last := zoneOrdinal(tr)
+			run = &zoneRun{transitions: []*zoneTransition{tr}, n: n, last: last}
+			runs = append(runs, run)
+			open[key] = run
+		}
+	}
+
+	// Transitions which aren't part of a rule are grouped by observance
+	type rdateKey struct {
+		offsetFrom time.Duration
+		offsetTo   time.Duration
+		name       string
+		dst        bool
+	}
+	rdateObservances := make(map[rdateKey]*Component)
+	rdates := make(map[*Component][]string)
+
+	for _, run := range runs {
+		first := run.transitions[0]
+		single := len(run.transitions) == 1 && !run.open
+		key := rdateKey{first.offsetFrom, first.offsetTo, first.name, first.dst}
+		if obs := rdateObservances[key]; single && obs != nil {
+			rdates[obs] = append(rdates[obs], first.wall().Format(datetimeFormat))
+			continue
+		}
+
+		obs := newObservance(first.dst, first.offsetFrom, first.offsetTo, first.name)
+		obs.Props.SetDateTimeValue(PropDateTimeStart, NewFloatingDateTime(first.wall()))
+		tz.Children = append(tz.Children, obs)
+
+		if single {
+			rdateObservances[key] = obs
+			continue
+		}
+
+		wall := first.wall()
+		day := WeekdayNum{N: run.n, Day: Sunday}
+		if run.last {
+			day.N = -1
+		}
+		for wd, weekday := range weekdays {
+			if weekday == wall.Weekday() {
+				day.Day = wd
+			}
+		}
+		recur := &Recur{
+			Freq:    FrequencyYearly,
+			ByDay:   []WeekdayNum{day},
+			ByMonth: []RecurMonth{{Month: int(wall.Month())}},
+		}
+		if !run.open {
+			recur.Until = run.transitions[len(run.transitions)-1].at
+		}
+		obs.Props.SetRecur(recur)
+	}
+
+	for obs, l := range rdates {
+		prop := NewProp(PropRecurrenceDates)
+		prop.SetValueType(ValueDateTime)
+		prop.Value = strings.Join(l, ",")
+		obs.Props.Set(prop)
+	}
+
+	return tz
+}
+
+func newObservance(dst bool, offsetFrom, offsetTo time.Duration, name string) *Component {
+	obs := NewComponent(CompTimezoneStandard)
+	if dst {
+		obs.Name = CompTimezoneDaylight
+	}
+	obs.Props.SetUTCOffset(PropTimezoneOffsetFrom, offsetFrom)
+	obs.Props.SetUTCOffset(PropTimezoneOffsetTo, offsetTo)
+	if name != "" {
+		obs.Props.SetText(PropTimezoneName, name)
+	}
+	return obs
+}
+
+// timezoneRange is the range of the date-times referencing a TZID. The range
+// is open if a recurrence referencing the TZID is unbounded.
+type timezoneRange struct {
+	start, end time.Time
+	open       bool
+}
+
+func (r *timezoneRange) add(t time.Time) {
+	if r.start.IsZero() || t.Before(r.start) {
+		r.start = t
+	}
+	if r.end.IsZero() || t.After(r.end) {
+		r.end = t
+	}
+}
+
+// collectTimezoneRanges collects the TZIDs referenced by a component and its
+// children, excluding VTIMEZONE components.
+func collectTimezoneRanges(comp *Component, ranges map[string]*timezoneRange, load timezoneLoader) error {
+	for _, props := range comp.Props {
+		for i := range props {
+			prop := &props[i]
+			tzid := prop.Params.Get(PropTimezoneID)
+			if tzid == "" {
+				continue
+			}
+			loc, err := load.load(tzid)
+			if err != nil {
+				return fmt.Errorf("ical: failed to load TZID %q: %v", tzid, err)
+			}
+
+			r := ranges[tzid]
+			if r == nil {
+				r = &timezoneRange{}
+				ranges[tzid] = r
+			}
+			switch prop.ValueType() {
+			case ValueDateTime, ValuePeriod:
+				l, err := prop.dateTimeList(loc, load)
+				if err != nil {
+					return err
+				}
+				for _, t := range l {
+					r.add(t)
+				}
+			}
+		}
+	}
+
+	// The recurrence and the last instance may end after the last date-time
+	if err := collectInstanceRange(comp, ranges, load); err != nil {
+		return err
+	}
+
+	for _, child := range comp.Children {
+		if child.Name == CompTimezone {
+			continue
+		}
+		if err := collectTimezoneRanges(child, ranges, load); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collectInstanceRange extends the ranges of the TZIDs of the start and the
+// end of a component up to the end of its last instance.
+func collectInstanceRange(comp *Component, ranges map[string]*timezoneRange, load timezoneLoader) error {
+	dtstart := comp.Props.Get(PropDateTimeStart)
+	if dtstart == nil {
+		return nil
+	}
+	endProp := comp.Props.Get(PropDateTimeEnd)
+	if endProp == nil {
+		endProp = comp.Props.Get(PropDue)
+	}
+
+	var l []*timezoneRange
+	for _, prop := range []*Prop{dtstart, endProp} {
+		if prop == nil {
+			continue
+		}
+		if r := ranges[prop.Params.Get(PropTimezoneID)]; r != nil {
+			l = append(l, r)
+		}
+	}
+	if len(l) == 0 {
+		return nil
+	}
+
+	start, err := dtstart.dateTime(dtstart.Value, time.UTC, load)
+	if err != nil {
+		return err
+	}
+	recur, err := comp.Props.Recur()
+	if err != nil {
+		return err
+	}
+	last := start
+	switch {
+	case recur == nil:
+		// Not recurring
+	case !recur.Until.IsZero():
+		last = recur.until(start.Location())
+	case recur.Count > 0:
+		set, err := comp.recurrenceSet(nil, load)
+		if err != nil {
+			return err
+		}
+		if all := set.All(); len(all) > 0 {
+			last = all[len(all)-1]
+		}
+	default:
+		for _, r := range l {
+			r.open = true
+		}
+		return nil
+	}
+
+	// Instances have the duration of the component
+	end := last
+	switch {
+	case endProp != nil:
+		t, err := endProp.dateTime(endProp.Value, time.UTC, load)
+		if err != nil {
+			return err
+		}
+		if dtstart.isDate() {
+			days := int(t.Sub(start).Hours()/24 + 0.5)
+			end = last.In(start.Location()).AddDate(0, 0, days)
+		} else {
+			end = last.Add(t.Sub(start))
+		}
+	case comp.Props.Get(PropDuration) != nil:
+		dur, err := comp.Props.Get(PropDuration).Duration()
+		if err != nil {
+			return err
+		}
+		end = dur.AddTo(last.In(start.Location()))
+	}
+	for _, r := range l {
+		r.add(last)
+		r.add(end)
+	}
+	return nil
+}
+
+// missingTimezones generates VTIMEZONE components for the TZIDs referenced in
+// the calendar but not defined by one of its VTIMEZONE components. Each
+// component covers the years of the date-times referencing it, and at least
+// two years.
+func (cal *Calendar) missingTimezones() ([]*Component, error) {
+	ranges := make(map[string]*timezoneRange)
+	if err := collectTimezoneRanges(cal.Component, ranges, timezoneLoader(cal.LoadLocation).cached()); err != nil {
+		return nil, err
+	}
+	for _, child := range cal.Children {
+		if child.Name != CompTimezone {
+			continue
+		}
+		if tzid, err := child.Props.Text(PropTimezoneID); err == nil {
+			delete(ranges, tzid)
+		}
+	}
+
+	tzids := make([]string, 0, len(ranges))
+	for tzid := range ranges {
+		tzids = append(tzids, tzid)
+	}
+	sort.Strings(tzids)
+
+	l := make([]*Component, 0, len(tzids))
+	for _, tzid := range tzids {
+		loc, err := DefaultTimezoneResolver.LoadLocation(tzid)
+		if err != nil {
+			return nil, err
+		}
+
+		r := ranges[tzid]
+		if r.start.IsZero() {
+			r.add(time.Now())
+		}
+		startYear := r.start.In(loc).Year()
+		endYear := r.end.In(loc).Year()
+		if endYear <= startYear {
+			endYear = startYear + 1
+		}
+		from := time.Date(startYear, time.January, 1, 0, 0, 0, 0, loc)
+		to := time.Date(endYear+1, time.January, 1, 0, 0, 0, 0, loc)
+		// The TZID may not be the name of the location, e.g. for Windows
+		// time zone names
+		tz := NewTimezone(loc, from, to)
+		tz.Props.SetText(PropTimezoneID, tzid)
+		l = append(l, tz)
+	}
+	return l, nil
+}
+
+// TrimTimezone returns a copy of a VTIMEZONE component describing the same
+// UTC offsets between start and end (exclusive), in the spirit of the
+// truncated time zones of RFC 7809. Observances which aren't in effect during
+// the range are removed. The remaining observances start with the onset in
+// effect at start, or their first onset in the range, and their recurrence
+// dates outside the range are removed. If end is zero, the component is only
+// trimmed before start.
+func TrimTimezone(tz *Component, start, end time.Time) (*Component, error) {
+	if tz.Name != CompTimezone {
+		return nil, fmt.Errorf("ical: expected a VTIMEZONE component, got %v", tz.Name)
+	}
+
+	scanEnd := end
+	if scanEnd.IsZero() {
+		scanEnd = time.Date(timezoneMaxYear, time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+	transitions, err := timezoneTransitions(tz, scanEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	// The transition in effect at start is always kept
+	cut := transitions[0].at
+	for _, tr := range transitions {
+		if tr.at.After(start) {
+			break
+		}
+		cut = tr.at
+	}
+	keep := func(at time.Time) bool {
+		return at.Equal(cut) || (at.After(cut) && (end.IsZero() || at.Before(end)))
+	}
+
+	trimmed := NewComponent(CompTimezone)
+	for name, l := range tz.Props {
+		trimmed.Props[name] = l
+	}
+	for _, child := range tz.Children {
+		if child.Name != CompTimezoneStandard && child.Name != CompTimezoneDaylight {
+			trimmed.Children = append(trimmed.Children, child)
+			continue
+		}
+
+		obs, err := parseObservance(child)
+		if err != nil {
+			return nil, err
+		}
+		comp, err := obs.trim(keep, scanEnd)
+		if err != nil {
+			return nil, err
+		} else if comp != nil {
+			trimmed.Children = append(trimmed.Children, comp)
+		}
+	}
+	return trimmed, nil
+}
+
+// trim returns a copy of the observance with the onsets for which keep
+// returns true, up to end. Nil is returned if no onset is kept.
+func (obs *observance) trim(keep func(at time.Time) bool, end time.Time) (*Component, error) {
+	keepWall := func(t time.Time) bool {
+		return keep(t.Add(-obs.offsetFrom))
+	}
+
+	comp := NewComponent(obs.comp.Name)
+	for name, l := range obs.comp.Props {
+		comp.Props[name] = l
+	}
+	comp.Children = obs.comp.Children
+
+	all, err := obs.rdates()
+	if err != nil {
+		return nil, err
+	}
+	var rdates []time.Time
+	for _, t := range all {
+		if keepWall(t) {
+			rdates = append(rdates, t)
+		}
+	}
+	sort.Slice(rdates, func(i, j int) bool {
+		return rdates[i].Before(rdates[j])
+	})
+
+	start := obs.start
+	if !keepWall(start) {
+		start = time.Time{}
+		if prop := comp.Props.Get(PropRecurrenceRule); prop != nil {
+			onsets, err := obs.ruleOnsets(end.Add(obs.offsetFrom))
+			if err != nil {
+				return nil, err
+			}
+			skipped := -1
+			for i, t := range onsets {
+				if keepWall(t) {
+					skipped = i
+					break
+				}
+			}
+
+			if skipped < 0 {
+				comp.Props.Del(PropRecurrenceRule)
+			} else {
+				start = onsets[skipped]
+				recur, err := prop.Recur()
+				if err != nil {
+					return nil, err
+				}
+				if recur.Count > 0 {
+					recur.Count -= skipped
+					comp.Props.SetRecur(recur)
+				}
+			}
+		}
+
+		if start.IsZero() {
+			if len(rdates) == 0 {
+				return nil, nil
+			}
+			start, rdates = rdates[0], rdates[1:]
+		}
+		comp.Props.SetDateTimeValue(PropDateTimeStart, DateTime{Form: DateTimeFloating, Wall: start})
+	}
+
+	comp.Props.Del(PropRecurrenceDates)
+	values := make([]string, 0, len(rdates))
+	for _, t := range rdates {
+		if !t.Equal(start) {
+			values = append(values, t.Format(datetimeFormat))
+		}
+	}
+	if len(values) > 0 {
+		prop := NewProp(PropRecurrenceDates)
+		prop.Value = strings.Join(values, ",")
+		comp.Props.Set(prop)
+	}
+
+	return comp, nil
+}
+
+// TrimTimezones replaces the VTIMEZONE components of the calendar with the
+// result of TrimTimezone, for the range of the date-times referencing them,
+// including the occurrences of recurring components up to the end of their
+// last instance, as defined by DTEND, DUE or DURATION. VTIMEZONE components
+// which aren't referenced are left alone.
+func (cal *Calendar) TrimTimezones() error {
+	ranges := make(map[string]*timezoneRange)
+	if err := collectTimezoneRanges(cal.Component, ranges, timezoneLoader(cal.LoadLocation).cached()); err != nil {
+		return err
+	}
+
+	for i, child := range cal.Children {
+		if child.Name != CompTimezone {
+			continue
+		}
+		tzid, err := child.Props.Text(PropTimezoneID)
+		if err != nil {
+			return err
+		}
+		r := ranges[tzid]
+		if r == nil || r.start.IsZero() {
+			continue
+		}
+
+		var end time.Time
+		if !r.open {
+			end = r.end.Add(time.Second)
+		}
+		tz, err := TrimTimezone(child, r.start, end)
+		if err != nil {
+			return err
+		}
+		cal.Children[i] = tz
+	}
+	return nil
+}
+
+// TimezoneDivergence is an interval during which the UTC offset defined by a
+// VTIMEZONE component differs from the one of the time zone database.
+type TimezoneDivergence struct {
+	Start, End time.Time
+	// Offset is the UTC offset defined by the VTIMEZONE component.
+	Offset time.Duration
+	// Want is the UTC offset defined by the time zone database.
+	Want time.Duration
+}
+
+// CompareTimezone compares the UTC offsets defined by a VTIMEZONE component
+// between start and end with the ones of the location loaded by
+// DefaultTimezoneResolver for its TZID, and returns the intervals during which
+// they differ. Time zone abbreviations are ignored.
+func CompareTimezone(tz *Component, start, end time.Time) ([]TimezoneDivergence, error) {
+	tzid, err := tz.Props.Text(PropTimezoneID)
+	if err != nil {
+		return nil, err
+	}
+	want, err := DefaultTimezoneResolver.LoadLocation(tzid)
+	if err != nil {
+		return nil, err
+	}
+	return compareTimezone(tz, want, start, end)
+}
+
+func compareTimezone(tz *Component, want *time.Location, start, end time.Time) ([]TimezoneDivergence, error) {
+	got, err := timezoneLocation(tz)
+	if err != nil {
+		return nil, err
+	}
+
+	// Offsets only change at the transitions of either location
+	bounds := []time.Time{start.UTC()}
+	for _, tr := range zoneTransitions(got, start, end) {
+		bounds = append(bounds, tr.at)
+	}
+	for _, tr := range zoneTransitions(want, start, end) {
+		bounds = append(bounds, tr.at)
+	}
+	sort.Slice(bounds, func(i, j int) bool {
+		return bounds[i].Before(bounds[j])
+	})
+
+	var l []TimezoneDivergence
+	for i, at := range bounds {
+		next := end.UTC()
+		if i+1 < len(bounds) {
+			next = bounds[i+1]
+		}
+		if !next.After(at) {
+			continue
+		}
+
+		_, offset := at.In(got).Zone()
+		_, wantOffset := at.In(want).Zone()
+		if offset == wantOffset {
+			continue
+		}
+		div := TimezoneDivergence{
+			Start:  at,
+			End:    next,
+			Offset: time.Duration(offset) * time.Second,
+			Want:   time.Duration(wantOffset) * time.Second,
+		}
+		if n := len(l); n > 0 && l[n-1].End.Equal(at) && l[n-1].Offset == div.Offset && l[n-1].Want == div.Want {
+			l[n-1].End = next
+			continue
+		}
+		l = append(l, div)
+	}
+	return l, nil
+}
+
+// UpdateTimezones replaces the VTIMEZONE components of the calendar which
+// differ from the time zone database between start and end, as reported by
+// CompareTimezone, with components generated by NewTimezone for the same
+// range. VTIMEZONE components with a TZID unknown to DefaultTimezoneResolver
+// are left alone. The TZIDs of the replaced components are returned.
+func (cal *Calendar) UpdateTimezones(start, end time.Time) ([]string, error) {
+	var updated []string
+	for i, child := range cal.Children {
+		if child.Name != CompTimezone {
+			continue
+		}
+		tzid, err := child.Props.Text(PropTimezoneID)
+		if err != nil {
+			return updated, err
+		}
+		loc, err := DefaultTimezoneResolver.LoadLocation(tzid)
+		if err != nil {
+			continue
+		}
+
+		l, err := compareTimezone(child, loc, start, end)
+		if err != nil {
+			return updated, err
+		} else if len(l) == 0 {
+			continue
+		}
+
+		tz := NewTimezone(loc, start, end)
+		tz.Props.SetText(PropTimezoneID, tzid)
+		cal.Children[i] = tz
+		updated = append(updated, tzid)
+	}
+	return updated, nil
+}
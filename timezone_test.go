@@ -0,0 +1,1001 @@
+package ical
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var customTimezoneCalendarStr = toCRLF(`BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//xyz Corp//NONSGML PDA Calendar Version 1.0//EN
+BEGIN:VTIMEZONE
+TZID:Customized Time Zone
+BEGIN:STANDARD
+DTSTART:16010101T030000
+TZOFFSETFROM:+0200
+TZOFFSETTO:+0100
+RRULE:FREQ=YEARLY;BYDAY=-1SU;BYMONTH=10
+END:STANDARD
+BEGIN:DAYLIGHT
+DTSTART:16010101T020000
+TZOFFSETFROM:+0100
+TZOFFSETTO:+0200
+RRULE:FREQ=YEARLY;BYDAY=-1SU;BYMONTH=3
+END:DAYLIGHT
+END:VTIMEZONE
+BEGIN:VTIMEZONE
+TZID:/example.org/Island
+BEGIN:STANDARD
+DTSTART:20000101T000000
+TZOFFSETFROM:+0300
+TZOFFSETTO:+0300
+TZNAME:IST
+RDATE:20240901T020000
+END:STANDARD
+BEGIN:DAYLIGHT
+DTSTART:20240401T020000
+TZOFFSETFROM:+0300
+TZOFFSETTO:+0400
+TZNAME:IDT
+END:DAYLIGHT
+END:VTIMEZONE
+BEGIN:VEVENT
+UID:custom@example.org
+DTSTAMP:20240101T000000Z
+DTSTART;TZID=Customized Time Zone:20240715T100000
+DTEND;TZID=Customized Time Zone:20240715T110000
+RRULE:FREQ=MONTHLY;COUNT=5
+EXDATE;TZID=Customized Time Zone:20240915T100000
+END:VEVENT
+BEGIN:VEVENT
+UID:island@example.org
+DTSTAMP:20240101T000000Z
+DTSTART;TZID=/example.org/Island:20240315T120000
+DTEND;TZID=/example.org/Island:20240615T120000
+END:VEVENT
+BEGIN:VEVENT
+UID:system@example.org
+DTSTAMP:20240101T000000Z
+DTSTART;TZID=America/New_York:20240715T100000
+END:VEVENT
+END:VCALENDAR
+`)
+
+func TestCalendarLoadLocation(t *testing.T) {
+	cal, err := NewDecoder(strings.NewReader(customTimezoneCalendarStr)).Decode()
+	if err != nil {
+		t.Fatalf("Decode() = %v", err)
+	}
+
+	loc, err := cal.LoadLocation("Customized Time Zone")
+	if err != nil {
+		t.Fatalf("Calendar.LoadLocation() = %v", err)
+	}
+	for _, tc := range []struct {
+		t      time.Time
+		offset int
+	}{
+		{time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC), 3600},
+		{time.Date(2024, 3, 31, 0, 59, 59, 0, time.UTC), 3600},
+		{time.Date(2024, 3, 31, 1, 0, 0, 0, time.UTC), 7200},
+		{time.Date(2024, 10, 27, 0, 59, 59, 0, time.UTC), 7200},
+		{time.Date(2024, 10, 27, 1, 0, 0, 0, time.UTC), 3600},
+		{time.Date(2150, 7, 1, 0, 0, 0, 0, time.UTC), 7200},
+	} {
+		if _, offset := tc.t.In(loc).Zone(); offset != tc.offset {
+			t.Errorf("offset at %v = %v, want %v", tc.t, offset, tc.offset)
+		}
+	}
+
+	if loc, err := cal.LoadLocation("America/New_York"); err != nil {
+		t.Errorf("Calendar.LoadLocation() = %v", err)
+	} else if loc.String() != "America/New_York" {
+		t.Errorf("Calendar.LoadLocation() = %v, want America/New_York", loc)
+	}
+	if _, err := cal.LoadLocation("/example.org/Unknown"); err == nil {
+		t.Errorf("Calendar.LoadLocation() = nil, want an error")
+	}
+}
+
+func TestCalendarLoadLocationCache(t *testing.T) {
+	cal, err := NewDecoder(strings.NewReader(customTimezoneCalendarStr)).Decode()
+	if err != nil {
+		t.Fatalf("Decode() = %v", err)
+	}
+
+	loc, err := cal.LoadLocation("/example.org/Island")
+	if err != nil {
+		t.Fatalf("Calendar.LoadLocation() = %v", err)
+	}
+	if cached, err := cal.LoadLocation("/example.org/Island"); err != nil || cached != loc {
+		t.Errorf("Calendar.LoadLocation() = %p, %v, want the cached location %p", cached, err, loc)
+	}
+
+	// Modified components aren't served from the cache
+	cal.Children[1].Children[1].Props.SetUTCOffset(PropTimezoneOffsetTo, 5*time.Hour)
+	modified, err := cal.LoadLocation("/example.org/Island")
+	if err != nil {
+		t.Fatalf("Calendar.LoadLocation() = %v", err)
+	}
+	at := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	if _, offset := at.In(modified).Zone(); offset != 5*3600 {
+		t.Errorf("offset at %v = %v, want %v", at, offset, 5*3600)
+	}
+}
+
+func TestEventCustomTimezone(t *testing.T) {
+	cal, err := NewDecoder(strings.NewReader(customTimezoneCalendarStr)).Decode()
+	if err != nil {
+		t.Fatalf("Decode() = %v", err)
+	}
+	events := cal.Events()
+
+	testCases := []struct {
+		start, end time.Time
+	}{
+		{
+			start: time.Date(2024, 7, 15, 8, 0, 0, 0, time.UTC),
+			end:   time.Date(2024, 7, 15, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			start: time.Date(2024, 3, 15, 9, 0, 0, 0, time.UTC),
+			end:   time.Date(2024, 6, 15, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			start: time.Date(2024, 7, 15, 14, 0, 0, 0, time.UTC),
+		},
+	}
+	for i, tc := range testCases {
+		event := &events[i]
+		if start, err := event.DateTimeStartWith(nil, cal); err != nil {
+			t.Errorf("Event.DateTimeStartWith() = %v", err)
+		} else if !start.Equal(tc.start) {
+			t.Errorf("Event.DateTimeStartWith() = %v, want %v", start, tc.start)
+		}
+		if tc.end.IsZero() {
+			continue
+		}
+		if end, err := event.DateTimeEndWith(nil, cal); err != nil {
+			t.Errorf("Event.DateTimeEndWith() = %v", err)
+		} else if !end.Equal(tc.end) {
+			t.Errorf("Event.DateTimeEndWith() = %v, want %v", end, tc.end)
+		}
+	}
+
+	set, err := events[0].RecurrenceSetWith(nil, cal)
+	if err != nil {
+		t.Fatalf("Event.RecurrenceSetWith() = %v", err)
+	}
+	want := []time.Time{
+		time.Date(2024, 7, 15, 8, 0, 0, 0, time.UTC),
+		time.Date(2024, 8, 15, 8, 0, 0, 0, time.UTC),
+		time.Date(2024, 10, 15, 8, 0, 0, 0, time.UTC),
+		time.Date(2024, 11, 15, 9, 0, 0, 0, time.UTC),
+	}
+	got := set.All()
+	if len(got) != len(want) {
+		t.Fatalf("Event.RecurrenceSetWith().All() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("occurrence %v = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	if _, err := events[0].Component.RecurrenceSet(nil); err == nil {
+		t.Errorf("Component.RecurrenceSet() = nil, want an error without the calendar")
+	}
+}
+
+func TestNewTimezone(t *testing.T) {
+	testCases := []struct {
+		name string
+		// Whether the time zone still follows the same rules after the range
+		open bool
+	}{
+		{"Europe/Paris", true},
+		{"America/New_York", true},
+		{"Australia/Lord_Howe", true},
+		{"Asia/Tokyo", true},
+		{"America/Sao_Paulo", false},
+		{"Africa/Casablanca", false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			loc, err := time.LoadLocation(tc.name)
+			if err != nil {
+				t.Skipf("time zone database unavailable: %v", err)
+			}
+			from := time.Date(2016, 1, 1, 0, 0, 0, 0, loc)
+			to := time.Date(2023, 1, 1, 0, 0, 0, 0, loc)
+
+			tz := NewTimezone(loc, from, to)
+			if tzid, _ := tz.Props.Text(PropTimezoneID); tzid != tc.name {
+				t.Errorf("TZID = %q, want %q", tzid, tc.name)
+			}
+			if err := checkComponent(tz); err != nil {
+				t.Errorf("checkComponent() = %v", err)
+			}
+
+			got, err := timezoneLocation(tz)
+			if err != nil {
+				t.Fatalf("timezoneLocation() = %v", err)
+			}
+			end := to
+			if tc.open {
+				end = to.AddDate(10, 0, 0)
+			}
+			for instant := from; instant.Before(end); instant = instant.Add(time.Hour) {
+				_, want := instant.In(loc).Zone()
+				if _, offset := instant.In(got).Zone(); offset != want {
+					t.Fatalf("offset at %v = %v, want %v", instant.UTC(), offset, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNewTimezoneStart(t *testing.T) {
+	testCases := []struct {
+		name string
+		from time.Time
+	}{
+		{"America/New_York", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"America/New_York", time.Date(2024, 3, 10, 7, 0, 0, 0, time.UTC)},
+		{"Europe/Paris", time.Date(2024, 7, 15, 10, 0, 0, 0, time.UTC)},
+		{"Australia/Sydney", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"Asia/Tokyo", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name+"/"+tc.from.Format(datetimeFormat), func(t *testing.T) {
+			loc, err := time.LoadLocation(tc.name)
+			if err != nil {
+				t.Skipf("time zone database unavailable: %v", err)
+			}
+			to := tc.from.AddDate(2, 0, 0)
+
+			tz := NewTimezone(loc, tc.from, to)
+			transitions, err := timezoneTransitions(tz, to)
+			if err != nil {
+				t.Fatalf("timezoneTransitions() = %v", err)
+			}
+
+			// The observance in effect at from must have an onset before it
+			var current *observance
+			for _, tr := range transitions {
+				if tr.at.After(tc.from) {
+					break
+				}
+				current = tr.obs
+			}
+			if current == nil {
+				t.Fatalf("NewTimezone() has no observance in effect at %v, first onset at %v", tc.from, transitions[0].at)
+			}
+			_, offset := tc.from.In(loc).Zone()
+			if want := time.Duration(offset) * time.Second; current.offsetTo != want {
+				t.Errorf("offset at %v = %v, want %v", tc.from, current.offsetTo, want)
+			}
+		})
+	}
+}
+
+func TestNewTimezoneRules(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+
+	tz := NewTimezone(loc, time.Date(2020, 1, 1, 0, 0, 0, 0, loc), time.Date(2021, 1, 1, 0, 0, 0, 0, loc))
+	want := map[string]string{
+		CompTimezoneDaylight: "FREQ=YEARLY;BYDAY=2SU;BYMONTH=3",
+		CompTimezoneStandard: "FREQ=YEARLY;BYDAY=1SU;BYMONTH=11",
+	}
+	if len(tz.Children) != len(want) {
+		t.Fatalf("NewTimezone() has %v observances, want %v", len(tz.Children), len(want))
+	}
+	for _, obs := range tz.Children {
+		if got := obs.Props.Get(PropRecurrenceRule); got == nil || got.Value != want[obs.Name] {
+			t.Errorf("%v RRULE = %v, want %v", obs.Name, got, want[obs.Name])
+		}
+	}
+}
+
+func TestEncoderAddTimezones(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+
+	cal := NewCalendar()
+	cal.Props.SetText(PropVersion, "2.0")
+	cal.Props.SetText(PropProductID, "-//xyz Corp//NONSGML PDA Calendar Version 1.0//EN")
+	event := NewEvent()
+	event.Props.SetText(PropUID, "uid@example.org")
+	event.Props.SetDateTime(PropDateTimeStamp, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	event.Props.SetDateTime(PropDateTimeStart, time.Date(2024, 7, 15, 10, 0, 0, 0, paris))
+	cal.Children = append(cal.Children, event.Component)
+
+	var sb strings.Builder
+	enc := NewEncoder(&sb)
+	enc.AddTimezones = true
+	if err := enc.Encode(cal); err != nil {
+		t.Fatalf("Encode() = %v", err)
+	}
+	if len(cal.Children) != 1 {
+		t.Errorf("Encode() modified the calendar")
+	}
+
+	decoded, err := NewDecoder(strings.NewReader(sb.String())).Decode()
+	if err != nil {
+		t.Fatalf("Decode() = %v", err)
+	}
+	if len(decoded.Children) != 2 || decoded.Children[0].Name != CompTimezone {
+		t.Fatalf("Encode() didn't add a VTIMEZONE:\n%v", sb.String())
+	}
+	if tzid, _ := decoded.Children[0].Props.Text(PropTimezoneID); tzid != "Europe/Paris" {
+		t.Errorf("TZID = %q, want Europe/Paris", tzid)
+	}
+
+	// Existing VTIMEZONE components are kept
+	sb.Reset()
+	if err := enc.Encode(decoded); err != nil {
+		t.Fatalf("Encode() = %v", err)
+	}
+	if n := strings.Count(sb.String(), "BEGIN:VTIMEZONE"); n != 1 {
+		t.Errorf("Encode() wrote %v VTIMEZONE components, want 1", n)
+	}
+}
+
+func TestLocalTimeDST(t *testing.T) {
+	testCases := []struct {
+		tzid  string
+		value string
+		want  time.Time
+	}{
+		// Gaps use the offset before the gap
+		{"Europe/Paris", "20240331T023000", time.Date(2024, 3, 31, 1, 30, 0, 0, time.UTC)},
+		{"America/New_York", "20240310T023000", time.Date(2024, 3, 10, 7, 30, 0, 0, time.UTC)},
+		{"Australia/Sydney", "20241006T023000", time.Date(2024, 10, 5, 16, 30, 0, 0, time.UTC)},
+		{"Australia/Lord_Howe", "20241006T021500", time.Date(2024, 10, 5, 15, 45, 0, 0, time.UTC)},
+		{"America/Santiago", "20240908T003000", time.Date(2024, 9, 8, 4, 30, 0, 0, time.UTC)},
+		// Overlaps use the first occurrence
+		{"Europe/Paris", "20241027T023000", time.Date(2024, 10, 27, 0, 30, 0, 0, time.UTC)},
+		{"America/New_York", "20241103T013000", time.Date(2024, 11, 3, 5, 30, 0, 0, time.UTC)},
+		{"Australia/Sydney", "20240407T023000", time.Date(2024, 4, 6, 15, 30, 0, 0, time.UTC)},
+		{"Australia/Lord_Howe", "20240407T014500", time.Date(2024, 4, 6, 14, 45, 0, 0, time.UTC)},
+		{"America/Santiago", "20240406T233000", time.Date(2024, 4, 7, 2, 30, 0, 0, time.UTC)},
+		// Times around the transitions are unaffected
+		{"Europe/Paris", "20240331T015959", time.Date(2024, 3, 31, 0, 59, 59, 0, time.UTC)},
+		{"Europe/Paris", "20240331T030000", time.Date(2024, 3, 31, 1, 0, 0, 0, time.UTC)},
+		{"Europe/Paris", "20241027T015959", time.Date(2024, 10, 26, 23, 59, 59, 0, time.UTC)},
+		{"Europe/Paris", "20241027T030000", time.Date(2024, 10, 27, 2, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.tzid+"/"+tc.value, func(t *testing.T) {
+			if _, err := time.LoadLocation(tc.tzid); err != nil {
+				t.Skipf("time zone database unavailable: %v", err)
+			}
+
+			prop := NewProp(PropDateTimeStart)
+			prop.Params.Set(PropTimezoneID, tc.tzid)
+			prop.Value = tc.value
+			if got, err := prop.DateTime(nil); err != nil {
+				t.Errorf("Prop.DateTime() = %v", err)
+			} else if !got.Equal(tc.want) {
+				t.Errorf("Prop.DateTime() = %v, want %v", got.UTC(), tc.want)
+			}
+
+			prop.SetValueType(ValueDateTime)
+			if l, err := prop.DateTimeList(nil); err != nil {
+				t.Errorf("Prop.DateTimeList() = %v", err)
+			} else if !l[0].Equal(tc.want) {
+				t.Errorf("Prop.DateTimeList() = %v, want %v", l[0].UTC(), tc.want)
+			}
+		})
+	}
+}
+
+func TestRecurrenceSetDST(t *testing.T) {
+	testCases := []struct {
+		name    string
+		tzid    string
+		dtstart string
+		rrule   string
+		exdate  string
+		bounded bool
+		want    []time.Time
+	}{
+		{
+			name:    "gap",
+			tzid:    "Europe/Paris",
+			dtstart: "20240329T023000",
+			rrule:   "FREQ=DAILY;COUNT=4",
+			bounded: true,
+			want: []time.Time{
+				time.Date(2024, 3, 29, 1, 30, 0, 0, time.UTC),
+				time.Date(2024, 3, 30, 1, 30, 0, 0, time.UTC),
+				time.Date(2024, 3, 31, 1, 30, 0, 0, time.UTC),
+				time.Date(2024, 4, 1, 0, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:    "overlap",
+			tzid:    "Europe/Paris",
+			dtstart: "20241026T023000",
+			rrule:   "FREQ=DAILY;COUNT=3",
+			bounded: true,
+			want: []time.Time{
+				time.Date(2024, 10, 26, 0, 30, 0, 0, time.UTC),
+				time.Date(2024, 10, 27, 0, 30, 0, 0, time.UTC),
+				time.Date(2024, 10, 28, 1, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:    "unbounded",
+			tzid:    "America/New_York",
+			dtstart: "20230312T023000",
+			rrule:   "FREQ=YEARLY;BYMONTH=3;BYDAY=2SU",
+			want: []time.Time{
+				time.Date(2023, 3, 12, 7, 30, 0, 0, time.UTC),
+				time.Date(2024, 3, 10, 7, 30, 0, 0, time.UTC),
+				time.Date(2025, 3, 9, 7, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:    "sub-daily rule across a gap",
+			tzid:    "America/New_York",
+			dtstart: "20240310T014000",
+			rrule:   "FREQ=MINUTELY;INTERVAL=20;COUNT=8",
+			bounded: true,
+			want: []time.Time{
+				time.Date(2024, 3, 10, 6, 40, 0, 0, time.UTC),
+				time.Date(2024, 3, 10, 7, 0, 0, 0, time.UTC),
+				time.Date(2024, 3, 10, 7, 20, 0, 0, time.UTC),
+				time.Date(2024, 3, 10, 7, 40, 0, 0, time.UTC),
+				time.Date(2024, 3, 10, 8, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:    "dtstart in gap",
+			tzid:    "America/New_York",
+			dtstart: "20240310T023000",
+			rrule:   "FREQ=DAILY;COUNT=3",
+			bounded: true,
+			want: []time.Time{
+				time.Date(2024, 3, 10, 7, 30, 0, 0, time.UTC),
+				time.Date(2024, 3, 11, 6, 30, 0, 0, time.UTC),
+				time.Date(2024, 3, 12, 6, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:    "exdate in overlap",
+			tzid:    "Australia/Sydney",
+			dtstart: "20240405T023000",
+			rrule:   "FREQ=DAILY;COUNT=4",
+			exdate:  "20240407T023000",
+			bounded: true,
+			want: []time.Time{
+				time.Date(2024, 4, 4, 15, 30, 0, 0, time.UTC),
+				time.Date(2024, 4, 5, 15, 30, 0, 0, time.UTC),
+				time.Date(2024, 4, 7, 16, 30, 0, 0, time.UTC),
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := time.LoadLocation(tc.tzid); err != nil {
+				t.Skipf("time zone database unavailable: %v", err)
+			}
+
+			event := NewEvent()
+			event.Props.Set(&Prop{
+				Name:   PropDateTimeStart,
+				Params: Params{PropTimezoneID: []string{tc.tzid}},
+				Value:  tc.dtstart,
+			})
+			event.Props.Set(&Prop{
+				Name:   PropRecurrenceRule,
+				Params: make(Params),
+				Value:  tc.rrule,
+			})
+			if tc.exdate != "" {
+				event.Props.Set(&Prop{
+					Name:   PropExceptionDates,
+					Params: Params{PropTimezoneID: []string{tc.tzid}},
+					Value:  tc.exdate,
+				})
+			}
+
+			check := func(name string, next func() (time.Time, bool)) {
+				var got []time.Time
+				for len(got) < len(tc.want) {
+					t, ok := next()
+					if !ok {
+						break
+					}
+					got = append(got, t)
+				}
+				if len(got) != len(tc.want) {
+					t.Fatalf("%v = %v, want %v", name, got, tc.want)
+				}
+				for i := range tc.want {
+					if !got[i].Equal(tc.want[i]) {
+						t.Errorf("%v occurrence %v = %v, want %v", name, i, got[i].UTC(), tc.want[i])
+					}
+				}
+				if _, ok := next(); ok && tc.bounded {
+					t.Errorf("%v has too many occurrences", name)
+				}
+			}
+
+			it, err := event.RecurrenceIterator(nil, nil)
+			if err != nil {
+				t.Fatalf("Event.RecurrenceIterator() = %v", err)
+			}
+			check("Event.RecurrenceIterator()", it.Next)
+
+			// Occurrences of unbounded rules are only adjusted lazily
+			if tc.bounded {
+				set, err := event.RecurrenceSet(nil)
+				if err != nil {
+					t.Fatalf("Event.RecurrenceSet() = %v", err)
+				}
+				check("Event.RecurrenceSet()", set.Iterator())
+			}
+		})
+	}
+}
+
+func TestCalendarOccurrencesDST(t *testing.T) {
+	if _, err := time.LoadLocation("America/New_York"); err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+
+	event := NewEvent()
+	event.Props.SetText(PropUID, "gap@example.org")
+	event.Props.Set(&Prop{
+		Name:   PropDateTimeStart,
+		Params: Params{PropTimezoneID: []string{"America/New_York"}},
+		Value:  "20230312T023000",
+	})
+	event.Props.Set(&Prop{
+		Name:   PropRecurrenceRule,
+		Params: make(Params),
+		Value:  "FREQ=YEARLY;BYMONTH=3;BYDAY=2SU",
+	})
+	cal := NewCalendar()
+	cal.Children = append(cal.Children, event.Component)
+
+	// Occurrences are adjusted more than 100 years after DTSTART
+	start := time.Date(2140, 3, 1, 0, 0, 0, 0, time.UTC)
+	l, err := cal.Occurrences(start, start.AddDate(0, 1, 0))
+	if err != nil {
+		t.Fatalf("Calendar.Occurrences() = %v", err)
+	}
+	want := time.Date(2140, 3, 13, 7, 30, 0, 0, time.UTC)
+	if len(l) != 1 || !l[0].Start.Equal(want) {
+		t.Errorf("Calendar.Occurrences() = %v, want a single occurrence at %v", l, want)
+	}
+}
+
+func TestTrimTimezone(t *testing.T) {
+	cal, err := NewDecoder(strings.NewReader(customTimezoneCalendarStr)).Decode()
+	if err != nil {
+		t.Fatalf("Decode() = %v", err)
+	}
+	tz := cal.Children[0]
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	trimmed, err := TrimTimezone(tz, start, end)
+	if err != nil {
+		t.Fatalf("TrimTimezone() = %v", err)
+	}
+	if err := checkComponent(trimmed); err != nil {
+		t.Errorf("checkComponent() = %v", err)
+	}
+
+	want := map[string]string{
+		CompTimezoneStandard: "20231029T030000",
+		CompTimezoneDaylight: "20240331T020000",
+	}
+	if len(trimmed.Children) != len(want) {
+		t.Fatalf("TrimTimezone() has %v observances, want %v", len(trimmed.Children), len(want))
+	}
+	for _, obs := range trimmed.Children {
+		if got := obs.Props.Get(PropDateTimeStart).Value; got != want[obs.Name] {
+			t.Errorf("%v DTSTART = %v, want %v", obs.Name, got, want[obs.Name])
+		}
+	}
+	if got := tz.Children[0].Props.Get(PropDateTimeStart).Value; got != "16010101T030000" {
+		t.Errorf("TrimTimezone() modified the original component: DTSTART = %v", got)
+	}
+
+	wantLoc, err := timezoneLocation(tz)
+	if err != nil {
+		t.Fatalf("timezoneLocation() = %v", err)
+	}
+	gotLoc, err := timezoneLocation(trimmed)
+	if err != nil {
+		t.Fatalf("timezoneLocation() = %v", err)
+	}
+	for instant := start; instant.Before(end); instant = instant.Add(time.Hour) {
+		_, want := instant.In(wantLoc).Zone()
+		if _, offset := instant.In(gotLoc).Zone(); offset != want {
+			t.Fatalf("offset at %v = %v, want %v", instant, offset, want)
+		}
+	}
+}
+
+func TestTrimTimezoneRules(t *testing.T) {
+	testCases := []struct {
+		name       string
+		tz         string
+		start, end time.Time
+		want       map[string][]string
+	}{
+		{
+			name: "count",
+			tz: `BEGIN:VTIMEZONE
+TZID:Count
+BEGIN:STANDARD
+DTSTART:20201025T030000
+TZOFFSETFROM:+0200
+TZOFFSETTO:+0100
+RRULE:FREQ=YEARLY;BYDAY=-1SU;BYMONTH=10;COUNT=10
+END:STANDARD
+BEGIN:DAYLIGHT
+DTSTART:20200329T020000
+TZOFFSETFROM:+0100
+TZOFFSETTO:+0200
+RRULE:FREQ=YEARLY;BYDAY=-1SU;BYMONTH=3;COUNT=10
+END:DAYLIGHT
+END:VTIMEZONE
+`,
+			start: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+			end:   time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC),
+			want: map[string][]string{
+				CompTimezoneDaylight: {"20240331T020000", "FREQ=YEARLY;BYDAY=-1SU;BYMONTH=3;COUNT=6"},
+			},
+		},
+		{
+			name: "rdate",
+			tz: `BEGIN:VTIMEZONE
+TZID:RDate
+BEGIN:STANDARD
+DTSTART:20000101T000000
+TZOFFSETFROM:+0300
+TZOFFSETTO:+0300
+RDATE:20230901T020000,20240901T020000
+END:STANDARD
+BEGIN:DAYLIGHT
+DTSTART:20230401T020000
+TZOFFSETFROM:+0300
+TZOFFSETTO:+0400
+RDATE:20240401T020000,20250401T020000
+END:DAYLIGHT
+END:VTIMEZONE
+`,
+			start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			end:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			want: map[string][]string{
+				CompTimezoneStandard: {"20230901T020000", "", "20240901T020000"},
+				CompTimezoneDaylight: {"20240401T020000", "", ""},
+			},
+		},
+		{
+			name: "rdate at dtstart",
+			tz: `BEGIN:VTIMEZONE
+TZID:RDateStart
+BEGIN:STANDARD
+DTSTART:20200101T000000
+TZOFFSETFROM:+0100
+TZOFFSETTO:+0100
+RDATE:20200101T000000
+END:STANDARD
+END:VTIMEZONE
+`,
+			start: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+			end:   time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+			want: map[string][]string{
+				CompTimezoneStandard: {"20200101T000000", "", ""},
+			},
+		},
+		{
+			name: "open",
+			tz: `BEGIN:VTIMEZONE
+TZID:Open
+BEGIN:STANDARD
+DTSTART:19700101T000000
+TZOFFSETFROM:+0100
+TZOFFSETTO:+0100
+END:STANDARD
+BEGIN:DAYLIGHT
+DTSTART:19800330T020000
+TZOFFSETFROM:+0100
+TZOFFSETTO:+0200
+RRULE:FREQ=YEARLY;BYDAY=-1SU;BYMONTH=3;UNTIL=19950326T010000Z
+END:DAYLIGHT
+BEGIN:STANDARD
+DTSTART:19800928T030000
+TZOFFSETFROM:+0200
+TZOFFSETTO:+0100
+RRULE:FREQ=YEARLY;BYDAY=-1SU;BYMONTH=9;UNTIL=19950924T010000Z
+END:STANDARD
+END:VTIMEZONE
+`,
+			start: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+			want: map[string][]string{
+				CompTimezoneStandard: {"19950924T030000", "FREQ=YEARLY;BYDAY=-1SU;BYMONTH=9;UNTIL=19950924T010000Z", ""},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cal, err := NewDecoder(strings.NewReader(toCRLF("BEGIN:VCALENDAR\n" + tc.tz + "END:VCALENDAR\n"))).Decode()
+			if err != nil {
+				t.Fatalf("Decode() = %v", err)
+			}
+			trimmed, err := TrimTimezone(cal.Children[0], tc.start, tc.end)
+			if err != nil {
+				t.Fatalf("TrimTimezone() = %v", err)
+			}
+
+			if len(trimmed.Children) != len(tc.want) {
+				t.Fatalf("TrimTimezone() has %v observances, want %v", len(trimmed.Children), len(tc.want))
+			}
+			for _, obs := range trimmed.Children {
+				want := tc.want[obs.Name]
+				if want == nil {
+					t.Errorf("TrimTimezone() kept %v", obs.Name)
+					continue
+				}
+				for i, name := range []string{PropDateTimeStart, PropRecurrenceRule, PropRecurrenceDates} {
+					var got string
+					if prop := obs.Props.Get(name); prop != nil {
+						got = prop.Value
+						if got == "" {
+							t.Errorf("%v has an empty %v", obs.Name, name)
+						}
+					}
+					if i < len(want) && got != want[i] {
+						t.Errorf("%v %v = %q, want %q", obs.Name, name, got, want[i])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCalendarTrimTimezones(t *testing.T) {
+	cal, err := NewDecoder(strings.NewReader(customTimezoneCalendarStr)).Decode()
+	if err != nil {
+		t.Fatalf("Decode() = %v", err)
+	}
+	if err := cal.TrimTimezones(); err != nil {
+		t.Fatalf("Calendar.TrimTimezones() = %v", err)
+	}
+
+	// The recurring event ends in November, after the end of DST
+	want := map[string]map[string]string{
+		"Customized Time Zone": {
+			CompTimezoneStandard: "20241027T030000",
+			CompTimezoneDaylight: "20240331T020000",
+		},
+		"/example.org/Island": {
+			CompTimezoneStandard: "20000101T000000",
+			CompTimezoneDaylight: "20240401T020000",
+		},
+	}
+	for _, tz := range cal.Children[:2] {
+		tzid, _ := tz.Props.Text(PropTimezoneID)
+		if len(tz.Children) != len(want[tzid]) {
+			t.Errorf("%v has %v observances, want %v", tzid, len(tz.Children), len(want[tzid]))
+		}
+		for _, obs := range tz.Children {
+			if got := obs.Props.Get(PropDateTimeStart).Value; got != want[tzid][obs.Name] {
+				t.Errorf("%v %v DTSTART = %v, want %v", tzid, obs.Name, got, want[tzid][obs.Name])
+			}
+		}
+	}
+	if rdate := cal.Children[1].Children[0].Props.Get(PropRecurrenceDates); rdate != nil {
+		t.Errorf("/example.org/Island RDATE = %v, want none", rdate.Value)
+	}
+
+	events := cal.Events()
+	set, err := events[0].RecurrenceSetWith(nil, cal)
+	if err != nil {
+		t.Fatalf("Event.RecurrenceSetWith() = %v", err)
+	}
+	if got := set.All(); len(got) != 4 || !got[3].Equal(time.Date(2024, 11, 15, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("Event.RecurrenceSetWith().All() = %v", got)
+	}
+}
+
+func TestCalendarTrimTimezonesInstanceEnd(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+
+	testCases := []struct {
+		name  string
+		props map[string]string
+		end   time.Time
+	}{
+		{
+			name: "duration",
+			props: map[string]string{
+				PropDateTimeStart: "20241026T200000",
+				PropDuration:      "PT12H",
+			},
+			end: time.Date(2024, 10, 27, 7, 0, 0, 0, paris),
+		},
+		{
+			name: "last instance",
+			props: map[string]string{
+				PropDateTimeStart:  "20250327T230000",
+				PropDateTimeEnd:    "20250328T050000",
+				PropRecurrenceRule: "FREQ=DAILY;COUNT=3",
+			},
+			end: time.Date(2025, 3, 30, 6, 0, 0, 0, paris),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cal := NewCalendar()
+			tz := NewTimezone(paris, time.Date(2020, 1, 1, 0, 0, 0, 0, paris), time.Date(2030, 1, 1, 0, 0, 0, 0, paris))
+			event := NewEvent()
+			for name, value := range tc.props {
+				prop := &Prop{Name: name, Params: make(Params), Value: value}
+				if name != PropRecurrenceRule && name != PropDuration {
+					prop.Params.Set(PropTimezoneID, "Europe/Paris")
+				}
+				event.Props.Set(prop)
+			}
+			cal.Children = append(cal.Children, tz, event.Component)
+
+			if err := cal.TrimTimezones(); err != nil {
+				t.Fatalf("Calendar.TrimTimezones() = %v", err)
+			}
+			loc, err := timezoneLocation(cal.Children[0])
+			if err != nil {
+				t.Fatalf("timezoneLocation() = %v", err)
+			}
+			_, want := tc.end.Zone()
+			if _, offset := tc.end.In(loc).Zone(); offset != want {
+				t.Errorf("offset at the end of the last instance %v = %v, want %v", tc.end, offset, want)
+			}
+		})
+	}
+}
+
+func TestEncoderAddTimezonesCustom(t *testing.T) {
+	if _, err := time.LoadLocation("America/New_York"); err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+
+	cal, err := NewDecoder(strings.NewReader(customTimezoneCalendarStr)).Decode()
+	if err != nil {
+		t.Fatalf("Decode() = %v", err)
+	}
+
+	var sb strings.Builder
+	enc := NewEncoder(&sb)
+	enc.AddTimezones = true
+	if err := enc.Encode(cal); err != nil {
+		t.Fatalf("Encode() = %v", err)
+	}
+	if n := strings.Count(sb.String(), "BEGIN:VTIMEZONE"); n != 3 {
+		t.Errorf("Encode() wrote %v VTIMEZONE components, want 3", n)
+	}
+}
+
+// Brazil abolished daylight saving time in 2019
+var outdatedTimezoneStr = `BEGIN:VTIMEZONE
+TZID:America/Sao_Paulo
+BEGIN:STANDARD
+DTSTART:20180218T000000
+TZOFFSETFROM:-0200
+TZOFFSETTO:-0300
+RRULE:FREQ=YEARLY;BYDAY=3SU;BYMONTH=2
+END:STANDARD
+BEGIN:DAYLIGHT
+DTSTART:20181104T000000
+TZOFFSETFROM:-0300
+TZOFFSETTO:-0200
+RRULE:FREQ=YEARLY;BYDAY=1SU;BYMONTH=11
+END:DAYLIGHT
+END:VTIMEZONE
+`
+
+func TestCompareTimezone(t *testing.T) {
+	if _, err := time.LoadLocation("America/Sao_Paulo"); err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+
+	cal, err := NewDecoder(strings.NewReader(toCRLF("BEGIN:VCALENDAR\n" + outdatedTimezoneStr + "END:VCALENDAR\n"))).Decode()
+	if err != nil {
+		t.Fatalf("Decode() = %v", err)
+	}
+
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	got, err := CompareTimezone(cal.Children[0], start, end)
+	if err != nil {
+		t.Fatalf("CompareTimezone() = %v", err)
+	}
+	want := []TimezoneDivergence{
+		{
+			Start:  time.Date(2019, 11, 3, 3, 0, 0, 0, time.UTC),
+			End:    time.Date(2020, 2, 16, 2, 0, 0, 0, time.UTC),
+			Offset: -2 * time.Hour,
+			Want:   -3 * time.Hour,
+		},
+		{
+			Start:  time.Date(2020, 11, 1, 3, 0, 0, 0, time.UTC),
+			End:    time.Date(2021, 2, 21, 2, 0, 0, 0, time.UTC),
+			Offset: -2 * time.Hour,
+			Want:   -3 * time.Hour,
+		},
+		{
+			Start:  time.Date(2021, 11, 7, 3, 0, 0, 0, time.UTC),
+			End:    end,
+			Offset: -2 * time.Hour,
+			Want:   -3 * time.Hour,
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CompareTimezone() = %v, want %v", got, want)
+	}
+
+	loc, _ := time.LoadLocation("America/Sao_Paulo")
+	if got, err := CompareTimezone(NewTimezone(loc, start, end), start, end); err != nil || len(got) != 0 {
+		t.Errorf("CompareTimezone(NewTimezone()) = %v, %v, want no divergence", got, err)
+	}
+
+	tz := NewComponent(CompTimezone)
+	tz.Props.SetText(PropTimezoneID, "Unknown")
+	if _, err := CompareTimezone(tz, start, end); err == nil {
+		t.Errorf("CompareTimezone() = nil, want an error for an unknown TZID")
+	}
+}
+
+func TestCalendarUpdateTimezones(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+
+	cal, err := NewDecoder(strings.NewReader(customTimezoneCalendarStr)).Decode()
+	if err != nil {
+		t.Fatalf("Decode() = %v", err)
+	}
+	outdated, err := NewDecoder(strings.NewReader(toCRLF("BEGIN:VCALENDAR\n" + outdatedTimezoneStr + "END:VCALENDAR\n"))).Decode()
+	if err != nil {
+		t.Fatalf("Decode() = %v", err)
+	}
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	upToDate := NewTimezone(paris, start, end)
+	cal.Children = append([]*Component{outdated.Children[0], upToDate}, cal.Children...)
+
+	updated, err := cal.UpdateTimezones(start, end)
+	if err != nil {
+		t.Fatalf("Calendar.UpdateTimezones() = %v", err)
+	}
+	if want := []string{"America/Sao_Paulo"}; !reflect.DeepEqual(updated, want) {
+		t.Errorf("Calendar.UpdateTimezones() = %v, want %v", updated, want)
+	}
+	if cal.Children[1] != upToDate {
+		t.Errorf("Calendar.UpdateTimezones() replaced an up-to-date VTIMEZONE")
+	}
+	if got, err := CompareTimezone(cal.Children[0], start, end); err != nil || len(got) != 0 {
+		t.Errorf("CompareTimezone() = %v, %v after the update, want no divergence", got, err)
+	}
+	if tzid, _ := cal.Children[2].Props.Text(PropTimezoneID); tzid != "Customized Time Zone" {
+		t.Errorf("Calendar.UpdateTimezones() modified unknown VTIMEZONE components")
+	}
+}
@@ -0,0 +1,449 @@
+package ical
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TimezoneResolver resolves a TZID to a location.
+//
+// Calendar implements TimezoneResolver: VTIMEZONE components of the calendar
+// take precedence over DefaultTimezoneResolver. Calendar.Resolver uses
+// another fallback. Resolvers can be passed to Prop.DateTimeWith,
+// Event.DateTimeStartWith, Component.RecurrenceSetWith, IteratorOptions and
+// ConvertOptions.
+type TimezoneResolver interface {
+	LoadLocation(tzid string) (*time.Location, error)
+}
+
+// TimezoneResolverFunc is an adapter to use a function as a TimezoneResolver.
+type TimezoneResolverFunc func(tzid string) (*time.Location, error)
+
+// LoadLocation calls f(tzid).
+func (f TimezoneResolverFunc) LoadLocation(tzid string) (*time.Location, error) {
+	return f(tzid)
+}
+
+// DefaultTimezoneResolver resolves TZIDs which aren't defined by a VTIMEZONE
+// component, e.g. in Prop.DateTime and Component.RecurrenceSet, when no
+// other resolver is given. It defaults to LoadLocation.
+//
+// DefaultTimezoneResolver is only a fallback: it must not be modified while
+// it may be in use by another goroutine, e.g. it should be set during
+// initialization. Pass a TimezoneResolver to the functions accepting one
+// instead to use different resolvers.
+var DefaultTimezoneResolver TimezoneResolver = TimezoneResolverFunc(LoadLocation)
+
+// LoadLocation returns the location for a TZID from the system time zone
+// database. In addition to IANA names, the following TZIDs are recognized:
+//
+//   - Windows time zone names, e.g. "Eastern Standard Time"
+//   - Windows display names, e.g. "(UTC+01:00) Amsterdam, Berlin, Bern, Rome,
+//     Stockholm, Vienna", including the older "(GMT+01:00)" form
+//   - Globally unique TZIDs ending with an IANA name, e.g.
+//     "/mozilla.org/20050126_1/America/New_York"
+func LoadLocation(tzid string) (*time.Location, error) {
+	loc, err := time.LoadLocation(tzid)
+	if err == nil {
+		return loc, nil
+	}
+
+	if name, ok := windowsTimezoneName(tzid); ok {
+		return time.LoadLocation(name)
+	}
+
+	if strings.HasPrefix(tzid, "/") {
+		// Try the shortest suffixes last, since they are the most likely
+		// to be ambiguous
+		l := strings.Split(strings.TrimPrefix(tzid, "/"), "/")
+		for i := 1; i < len(l); i++ {
+			if loc, err := time.LoadLocation(strings.Join(l[i:], "/")); err == nil {
+				return loc, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("ical: unknown time zone %q", tzid)
+}
+
+// windowsTimezoneName returns the IANA name for a Windows time zone name or
+// display name.
+func windowsTimezoneName(tzid string) (string, bool) {
+	tzid = strings.TrimSpace(tzid)
+	if strings.HasPrefix(tzid, "(") {
+		key, ok := windowsDisplayNameKey(tzid)
+		if !ok {
+			return "", false
+		}
+		tzid, ok = windowsDisplayNameIndex[key]
+		if !ok {
+			return "", false
+		}
+	}
+
+	key := strings.ToLower(tzid)
+	if strings.HasSuffix(key, " daylight time") {
+		key = strings.TrimSuffix(key, " daylight time") + " standard time"
+	}
+	name, ok := windowsTimezoneIndex[key]
+	return name, ok
+}
+
+// windowsDisplayNameKey normalizes a Windows display name to its UTC offset
+// and its first place name, e.g. "+01:00 amsterdam". Display names are often
+// truncated, and the list of places changes between Windows versions.
+func windowsDisplayNameKey(s string) (string, bool) {
+	i := strings.IndexByte(s, ')')
+	if i < 0 {
+		return "", false
+	}
+	offset, places := s[1:i], strings.TrimSpace(s[i+1:])
+
+	if !strings.HasPrefix(offset, "UTC") && !strings.HasPrefix(offset, "GMT") {
+		return "", false
+	}
+	offset = strings.Replace(offset[3:], ".", ":", 1)
+	if offset == "" {
+		offset = "+00:00"
+	}
+	if len(offset) != len("+00:00") || (offset[0] != '+' && offset[0] != '-') {
+		return "", false
+	}
+
+	if i := strings.IndexAny(places, ",/"); i >= 0 {
+		places = places[:i]
+	}
+	places = strings.TrimRight(strings.TrimSpace(places), ".")
+	return offset + " " + strings.ToLower(places), true
+}
+
+var (
+	windowsTimezoneIndex    = make(map[string]string, len(windowsTimezones))
+	windowsDisplayNameIndex = make(map[string]string, len(windowsDisplayNames))
+)
+
+func init() {
+	for name, iana := range windowsTimezones {
+		windowsTimezoneIndex[strings.ToLower(name)] = iana
+	}
+	for display, name := range windowsDisplayNames {
+		key, ok := windowsDisplayNameKey(display)
+		if !ok {
+			panic(fmt.Sprintf("ical: invalid Windows display name %q", display))
+		}
+		if prev, ok := windowsDisplayNameIndex[key]; ok && prev != name {
+			panic(fmt.Sprintf("ical: ambiguous Windows display name %q", display))
+		}
+		windowsDisplayNameIndex[key] = name
+	}
+}
+
+// windowsTimezones maps Windows time zone names to IANA names. It is based
+// on the CLDR windowsZones data, with the "001" territory, and includes
+// names which are no longer used by Windows.
+var windowsTimezones = map[string]string{
+	"Egypt Standard Time":             "Africa/Cairo",
+	"Morocco Standard Time":           "Africa/Casablanca",
+	"South Africa Standard Time":      "Africa/Johannesburg",
+	"South Sudan Standard Time":       "Africa/Juba",
+	"Sudan Standard Time":             "Africa/Khartoum",
+	"W. Central Africa Standard Time": "Africa/Lagos",
+	"E. Africa Standard Time":         "Africa/Nairobi",
+	"Sao Tome Standard Time":          "Africa/Sao_Tome",
+	"Libya Standard Time":             "Africa/Tripoli",
+	"Namibia Standard Time":           "Africa/Windhoek",
+	"Aleutian Standard Time":          "America/Adak",
+	"Alaskan Standard Time":           "America/Anchorage",
+	"Tocantins Standard Time":         "America/Araguaina",
+	"Paraguay Standard Time":          "America/Asuncion",
+	"Bahia Standard Time":             "America/Bahia",
+	"SA Pacific Standard Time":        "America/Bogota",
+	"Argentina Standard Time":         "America/Buenos_Aires",
+	"Eastern Standard Time (Mexico)":  "America/Cancun",
+	"Venezuela Standard Time":         "America/Caracas",
+	"SA Eastern Standard Time":        "America/Cayenne",
+	"Central Standard Time":           "America/Chicago",
+	"Central Brazilian Standard Time": "America/Cuiaba",
+	"Mountain Standard Time":          "America/Denver",
+	"Greenland Standard Time":         "America/Godthab",
+	"Turks And Caicos Standard Time":  "America/Grand_Turk",
+	"Central America Standard Time":   "America/Guatemala",
+	"Atlantic Standard Time":          "America/Halifax",
+	"Cuba Standard Time":              "America/Havana",
+	"US Eastern Standard Time":        "America/Indianapolis",
+	"SA Western Standard Time":        "America/La_Paz",
+	"Pacific Standard Time":           "America/Los_Angeles",
+	"Mountain Standard Time (Mexico)": "America/Mazatlan",
+	"Central Standard Time (Mexico)":  "America/Mexico_City",
+	"Saint Pierre Standard Time":      "America/Miquelon",
+	"Montevideo Standard Time":        "America/Montevideo",
+	"Eastern Standard Time":           "America/New_York",
+	"US Mountain Standard Time":       "America/Phoenix",
+	"Haiti Standard Time":             "America/Port-au-Prince",
+	"Magallanes Standard Time":        "America/Punta_Arenas",
+	"Canada Central Standard Time":    "America/Regina",
+	"Pacific SA Standard Time":        "America/Santiago",
+	"E. South America Standard Time":  "America/Sao_Paulo",
+	"Newfoundland Standard Time":      "America/St_Johns",
+	"Pacific Standard Time (Mexico)":  "America/Tijuana",
+	"Yukon Standard Time":             "America/Whitehorse",
+	"Jordan Standard Time":            "Asia/Amman",
+	"Arabic Standard Time":            "Asia/Baghdad",
+	"Azerbaijan Standard Time":        "Asia/Baku",
+	"SE Asia Standard Time":           "Asia/Bangkok",
+	"Altai Standard Time":             "Asia/Barnaul",
+	"Middle East Standard Time":       "Asia/Beirut",
+	"Central Asia Standard Time":      "Asia/Bishkek",
+	"India Standard Time":             "Asia/Calcutta",
+	"Transbaikal Standard Time":       "Asia/Chita",
+	"Sri Lanka Standard Time":         "Asia/Colombo",
+	"Syria Standard Time":             "Asia/Damascus",
+	"Bangladesh Standard Time":        "Asia/Dhaka",
+	"Arabian Standard Time":           "Asia/Dubai",
+	"West Bank Standard Time":         "Asia/Hebron",
+	"W. Mongolia Standard Time":       "Asia/Hovd",
+	"North Asia East Standard Time":   "Asia/Irkutsk",
+	"Israel Standard Time":            "Asia/Jerusalem",
+	"Afghanistan Standard Time":       "Asia/Kabul",
+	"Russia Time Zone 11":             "Asia/Kamchatka",
+	"Pakistan Standard Time":          "Asia/Karachi",
+	"Nepal Standard Time":             "Asia/Katmandu",
+	"North Asia Standard Time":        "Asia/Krasnoyarsk",
+	"Magadan Standard Time":           "Asia/Magadan",
+	"N. Central Asia Standard Time":   "Asia/Novosibirsk",
+	"Omsk Standard Time":              "Asia/Omsk",
+	"North Korea Standard Time":       "Asia/Pyongyang",
+	"Qyzylorda Standard Time":         "Asia/Qyzylorda",
+	"Myanmar Standard Time":           "Asia/Rangoon",
+	"Arab Standard Time":              "Asia/Riyadh",
+	"Sakhalin Standard Time":          "Asia/Sakhalin",
+	"Korea Standard Time":             "Asia/Seoul",
+	"China Standard Time":             "Asia/Shanghai",
+	"Singapore Standard Time":         "Asia/Singapore",
+	"Russia Time Zone 10":             "Asia/Srednekolymsk",
+	"Taipei Standard Time":            "Asia/Taipei",
+	"West Asia Standard Time":         "Asia/Tashkent",
+	"Georgian Standard Time":          "Asia/Tbilisi",
+	"Iran Standard Time":              "Asia/Tehran",
+	"Tokyo Standard Time":             "Asia/Tokyo",
+	"Tomsk Standard Time":             "Asia/Tomsk",
+	"Ulaanbaatar Standard Time":       "Asia/Ulaanbaatar",
+	"Vladivostok Standard Time":       "Asia/Vladivostok",
+	"Yakutsk Standard Time":           "Asia/Yakutsk",
+	"Ekaterinburg Standard Time":      "Asia/Yekaterinburg",
+	"Caucasus Standard Time":          "Asia/Yerevan",
+	"Azores Standard Time":            "Atlantic/Azores",
+	"Cape Verde Standard Time":        "Atlantic/Cape_Verde",
+	"Greenwich Standard Time":         "Atlantic/Reykjavik",
+	"Cen. Australia Standard Time":    "Australia/Adelaide",
+	"E. Australia Standard Time":      "Australia/Brisbane",
+	"AUS Central Standard Time":       "Australia/Darwin",
+	"Aus Central W. Standard Time":    "Australia/Eucla",
+	"Tasmania Standard Time":          "Australia/Hobart",
+	"Lord Howe Standard Time":         "Australia/Lord_Howe",
+	"W. Australia Standard Time":      "Australia/Perth",
+	"AUS Eastern Standard Time":       "Australia/Sydney",
+	"UTC-11":                          "Etc/GMT+11",
+	"Dateline Standard Time":          "Etc/GMT+12",
+	"UTC-02":                          "Etc/GMT+2",
+	"UTC-08":                          "Etc/GMT+8",
+	"UTC-09":                          "Etc/GMT+9",
+	"UTC+12":                          "Etc/GMT-12",
+	"UTC+13":                          "Etc/GMT-13",
+	"UTC":                             "Etc/UTC",
+	"Astrakhan Standard Time":         "Europe/Astrakhan",
+	"W. Europe Standard Time":         "Europe/Berlin",
+	"GTB Standard Time":               "Europe/Bucharest",
+	"Central Europe Standard Time":    "Europe/Budapest",
+	"E. Europe Standard Time":         "Europe/Chisinau",
+	"Turkey Standard Time":            "Europe/Istanbul",
+	"Kaliningrad Standard Time":       "Europe/Kaliningrad",
+	"FLE Standard Time":               "Europe/Kiev",
+	"GMT Standard Time":               "Europe/London",
+	"Belarus Standard Time":           "Europe/Minsk",
+	"Russian Standard Time":           "Europe/Moscow",
+	"Romance Standard Time":           "Europe/Paris",
+	"Russia Time Zone 3":              "Europe/Samara",
+	"Saratov Standard Time":           "Europe/Saratov",
+	"Volgograd Standard Time":         "Europe/Volgograd",
+	"Central European Standard Time":  "Europe/Warsaw",
+	"Mauritius Standard Time":         "Indian/Mauritius",
+	"Samoa Standard Time":             "Pacific/Apia",
+	"New Zealand Standard Time":       "Pacific/Auckland",
+	"Bougainville Standard Time":      "Pacific/Bougainville",
+	"Chatham Islands Standard Time":   "Pacific/Chatham",
+	"Easter Island Standard Time":     "Pacific/Easter",
+	"Fiji Standard Time":              "Pacific/Fiji",
+	"Central Pacific Standard Time":   "Pacific/Guadalcanal",
+	"Hawaiian Standard Time":          "Pacific/Honolulu",
+	"Line Islands Standard Time":      "Pacific/Kiritimati",
+	"Marquesas Standard Time":         "Pacific/Marquesas",
+	"Norfolk Standard Time":           "Pacific/Norfolk",
+	"West Pacific Standard Time":      "Pacific/Port_Moresby",
+	"Tonga Standard Time":             "Pacific/Tongatapu",
+
+	// Legacy names
+	"Armenian Standard Time":     "Asia/Yerevan",
+	"Kamchatka Standard Time":    "Asia/Kamchatka",
+	"Mexico Standard Time":       "America/Mexico_City",
+	"Mexico Standard Time 2":     "America/Chihuahua",
+	"Mid-Atlantic Standard Time": "Etc/GMT+2",
+}
+
+// windowsDisplayNames maps Windows display names to Windows time zone names.
+var windowsDisplayNames = map[string]string{
+	"(UTC-12:00) International Date Line West":                      "Dateline Standard Time",
+	"(UTC-11:00) Coordinated Universal Time-11":                     "UTC-11",
+	"(UTC-10:00) Aleutian Islands":                                  "Aleutian Standard Time",
+	"(UTC-10:00) Hawaii":                                            "Hawaiian Standard Time",
+	"(UTC-09:30) Marquesas Islands":                                 "Marquesas Standard Time",
+	"(UTC-09:00) Alaska":                                            "Alaskan Standard Time",
+	"(UTC-09:00) Coordinated Universal Time-09":                     "UTC-09",
+	"(UTC-08:00) Baja California":                                   "Pacific Standard Time (Mexico)",
+	"(UTC-08:00) Coordinated Universal Time-08":                     "UTC-08",
+	"(UTC-08:00) Pacific Time (US & Canada)":                        "Pacific Standard Time",
+	"(UTC-07:00) Arizona":                                           "US Mountain Standard Time",
+	"(UTC-07:00) Chihuahua, La Paz, Mazatlan":                       "Mountain Standard Time (Mexico)",
+	"(UTC-07:00) La Paz, Mazatlan":                                  "Mountain Standard Time (Mexico)",
+	"(UTC-07:00) Mountain Time (US & Canada)":                       "Mountain Standard Time",
+	"(UTC-07:00) Yukon":                                             "Yukon Standard Time",
+	"(UTC-06:00) Central America":                                   "Central America Standard Time",
+	"(UTC-06:00) Central Time (US & Canada)":                        "Central Standard Time",
+	"(UTC-06:00) Easter Island":                                     "Easter Island Standard Time",
+	"(UTC-06:00) Guadalajara, Mexico City, Monterrey":               "Central Standard Time (Mexico)",
+	"(UTC-06:00) Saskatchewan":                                      "Canada Central Standard Time",
+	"(UTC-05:00) Bogota, Lima, Quito, Rio Branco":                   "SA Pacific Standard Time",
+	"(UTC-05:00) Chetumal":                                          "Eastern Standard Time (Mexico)",
+	"(UTC-05:00) Eastern Time (US & Canada)":                        "Eastern Standard Time",
+	"(UTC-05:00) Haiti":                                             "Haiti Standard Time",
+	"(UTC-05:00) Havana":                                            "Cuba Standard Time",
+	"(UTC-05:00) Indiana (East)":                                    "US Eastern Standard Time",
+	"(UTC-05:00) Turks and Caicos":                                  "Turks And Caicos Standard Time",
+	"(UTC-04:00) Asuncion":                                          "Paraguay Standard Time",
+	"(UTC-04:00) Atlantic Time (Canada)":                            "Atlantic Standard Time",
+	"(UTC-04:00) Caracas":                                           "Venezuela Standard Time",
+	"(UTC-04:00) Cuiaba":                                            "Central Brazilian Standard Time",
+	"(UTC-04:00) Georgetown, La Paz, Manaus, San Juan":              "SA Western Standard Time",
+	"(UTC-04:00) Santiago":                                          "Pacific SA Standard Time",
+	"(UTC-03:30) Newfoundland":                                      "Newfoundland Standard Time",
+	"(UTC-03:00) Araguaina":                                         "Tocantins Standard Time",
+	"(UTC-03:00) Brasilia":                                          "E. South America Standard Time",
+	"(UTC-03:00) Cayenne, Fortaleza":                                "SA Eastern Standard Time",
+	"(UTC-03:00) City of Buenos Aires":                              "Argentina Standard Time",
+	"(UTC-03:00) Buenos Aires":                                      "Argentina Standard Time",
+	"(UTC-03:00) Greenland":                                         "Greenland Standard Time",
+	"(UTC-03:00) Montevideo":                                        "Montevideo Standard Time",
+	"(UTC-03:00) Punta Arenas":                                      "Magallanes Standard Time",
+	"(UTC-03:00) Saint Pierre and Miquelon":                         "Saint Pierre Standard Time",
+	"(UTC-03:00) Salvador":                                          "Bahia Standard Time",
+	"(UTC-02:00) Coordinated Universal Time-02":                     "UTC-02",
+	"(UTC-02:00) Mid-Atlantic - Old":                                "Mid-Atlantic Standard Time",
+	"(UTC-01:00) Azores":                                            "Azores Standard Time",
+	"(UTC-01:00) Cabo Verde Is.":                                    "Cape Verde Standard Time",
+	"(UTC-01:00) Cape Verde Is.":                                    "Cape Verde Standard Time",
+	"(UTC) Coordinated Universal Time":                              "UTC",
+	"(UTC) Dublin, Edinburgh, Lisbon, London":                       "GMT Standard Time",
+	"(UTC) Monrovia, Reykjavik":                                     "Greenwich Standard Time",
+	"(UTC) Casablanca":                                              "Morocco Standard Time",
+	"(UTC+01:00) Casablanca":                                        "Morocco Standard Time",
+	"(UTC+00:00) Sao Tome":                                          "Sao Tome Standard Time",
+	"(UTC+01:00) Amsterdam, Berlin, Bern, Rome, Stockholm, Vienna":  "W. Europe Standard Time",
+	"(UTC+01:00) Belgrade, Bratislava, Budapest, Ljubljana, Prague": "Central Europe Standard Time",
+	"(UTC+01:00) Brussels, Copenhagen, Madrid, Paris":               "Romance Standard Time",
+	"(UTC+01:00) Sarajevo, Skopje, Warsaw, Zagreb":                  "Central European Standard Time",
+	"(UTC+01:00) West Central Africa":                               "W. Central Africa Standard Time",
+	"(UTC+02:00) Amman":                                             "Jordan Standard Time",
+	"(UTC+03:00) Amman":                                             "Jordan Standard Time",
+	"(UTC+02:00) Athens, Bucharest":                                 "GTB Standard Time",
+	"(UTC+02:00) Beirut":                                            "Middle East Standard Time",
+	"(UTC+02:00) Cairo":                                             "Egypt Standard Time",
+	"(UTC+02:00) Chisinau":                                          "E. Europe Standard Time",
+	"(UTC+02:00) Damascus":                                          "Syria Standard Time",
+	"(UTC+03:00) Damascus":                                          "Syria Standard Time",
+	"(UTC+02:00) Gaza, Hebron":                                      "West Bank Standard Time",
+	"(UTC+02:00) Harare, Pretoria":                                  "South Africa Standard Time",
+	"(UTC+02:00) Helsinki, Kyiv, Riga, Sofia, Tallinn, Vilnius":     "FLE Standard Time",
+	"(UTC+02:00) Jerusalem":                                         "Israel Standard Time",
+	"(UTC+02:00) Juba":                                              "South Sudan Standard Time",
+	"(UTC+02:00) Kaliningrad":                                       "Kaliningrad Standard Time",
+	"(UTC+02:00) Khartoum":                                          "Sudan Standard Time",
+	"(UTC+02:00) Tripoli":                                           "Libya Standard Time",
+	"(UTC+02:00) Windhoek":                                          "Namibia Standard Time",
+	"(UTC+03:00) Baghdad":                                           "Arabic Standard Time",
+	"(UTC+03:00) Istanbul":                                          "Turkey Standard Time",
+	"(UTC+03:00) Kuwait, Riyadh":                                    "Arab Standard Time",
+	"(UTC+03:00) Minsk":                                             "Belarus Standard Time",
+	"(UTC+03:00) Moscow, St. Petersburg":                            "Russian Standard Time",
+	"(UTC+03:00) Moscow, St. Petersburg, Volgograd":                 "Russian Standard Time",
+	"(UTC+03:00) Nairobi":                                           "E. Africa Standard Time",
+	"(UTC+03:00) Volgograd":                                         "Volgograd Standard Time",
+	"(UTC+04:00) Volgograd":                                         "Volgograd Standard Time",
+	"(UTC+03:30) Tehran":                                            "Iran Standard Time",
+	"(UTC+04:00) Abu Dhabi, Muscat":                                 "Arabian Standard Time",
+	"(UTC+04:00) Astrakhan, Ulyanovsk":                              "Astrakhan Standard Time",
+	"(UTC+04:00) Baku":                                              "Azerbaijan Standard Time",
+	"(UTC+04:00) Izhevsk, Samara":                                   "Russia Time Zone 3",
+	"(UTC+04:00) Port Louis":                                        "Mauritius Standard Time",
+	"(UTC+04:00) Saratov":                                           "Saratov Standard Time",
+	"(UTC+04:00) Tbilisi":                                           "Georgian Standard Time",
+	"(UTC+04:00) Yerevan":                                           "Caucasus Standard Time",
+	"(UTC+04:30) Kabul":                                             "Afghanistan Standard Time",
+	"(UTC+05:00) Ashgabat, Tashkent":                                "West Asia Standard Time",
+	"(UTC+05:00) Ekaterinburg":                                      "Ekaterinburg Standard Time",
+	"(UTC+05:00) Islamabad, Karachi":                                "Pakistan Standard Time",
+	"(UTC+05:00) Qyzylorda":                                         "Qyzylorda Standard Time",
+	"(UTC+05:30) Chennai, Kolkata, Mumbai, New Delhi":               "India Standard Time",
+	"(UTC+05:30) Sri Jayawardenepura":                               "Sri Lanka Standard Time",
+	"(UTC+05:45) Kathmandu":                                         "Nepal Standard Time",
+	"(UTC+06:00) Astana":                                            "Central Asia Standard Time",
+	"(UTC+06:00) Bishkek":                                           "Central Asia Standard Time",
+	"(UTC+06:00) Dhaka":                                             "Bangladesh Standard Time",
+	"(UTC+06:00) Omsk":                                              "Omsk Standard Time",
+	"(UTC+06:30) Yangon (Rangoon)":                                  "Myanmar Standard Time",
+	"(UTC+07:00) Bangkok, Hanoi, Jakarta":                           "SE Asia Standard Time",
+	"(UTC+07:00) Barnaul, Gorno-Altaysk":                            "Altai Standard Time",
+	"(UTC+07:00) Hovd":                                              "W. Mongolia Standard Time",
+	"(UTC+07:00) Krasnoyarsk":                                       "North Asia Standard Time",
+	"(UTC+07:00) Novosibirsk":                                       "N. Central Asia Standard Time",
+	"(UTC+07:00) Tomsk":                                             "Tomsk Standard Time",
+	"(UTC+08:00) Beijing, Chongqing, Hong Kong, Urumqi":             "China Standard Time",
+	"(UTC+08:00) Irkutsk":                                           "North Asia East Standard Time",
+	"(UTC+08:00) Kuala Lumpur, Singapore":                           "Singapore Standard Time",
+	"(UTC+08:00) Perth":                                             "W. Australia Standard Time",
+	"(UTC+08:00) Taipei":                                            "Taipei Standard Time",
+	"(UTC+08:00) Ulaanbaatar":                                       "Ulaanbaatar Standard Time",
+	"(UTC+08:45) Eucla":                                             "Aus Central W. Standard Time",
+	"(UTC+09:00) Chita":                                             "Transbaikal Standard Time",
+	"(UTC+09:00) Osaka, Sapporo, Tokyo":                             "Tokyo Standard Time",
+	"(UTC+09:00) Pyongyang":                                         "North Korea Standard Time",
+	"(UTC+09:00) Seoul":                                             "Korea Standard Time",
+	"(UTC+09:00) Yakutsk":                                           "Yakutsk Standard Time",
+	"(UTC+09:30) Adelaide":                                          "Cen. Australia Standard Time",
+	"(UTC+09:30) Darwin":                                            "AUS Central Standard Time",
+	"(UTC+10:00) Brisbane":                                          "E. Australia Standard Time",
+	"(UTC+10:00) Canberra, Melbourne, Sydney":                       "AUS Eastern Standard Time",
+	"(UTC+10:00) Guam, Port Moresby":                                "West Pacific Standard Time",
+	"(UTC+10:00) Hobart":                                            "Tasmania Standard Time",
+	"(UTC+10:00) Vladivostok":                                       "Vladivostok Standard Time",
+	"(UTC+10:30) Lord Howe Island":                                  "Lord Howe Standard Time",
+	"(UTC+11:00) Bougainville Island":                               "Bougainville Standard Time",
+	"(UTC+11:00) Chokurdakh":                                        "Russia Time Zone 10",
+	"(UTC+11:00) Magadan":                                           "Magadan Standard Time",
+	"(UTC+11:00) Norfolk Island":                                    "Norfolk Standard Time",
+	"(UTC+11:00) Sakhalin":                                          "Sakhalin Standard Time",
+	"(UTC+11:00) Solomon Is., New Caledonia":                        "Central Pacific Standard Time",
+	"(UTC+12:00) Anadyr, Petropavlovsk-Kamchatsky":                  "Russia Time Zone 11",
+	"(UTC+12:00) Auckland, Wellington":                              "New Zealand Standard Time",
+	"(UTC+12:00) Coordinated Universal Time+12":                     "UTC+12",
+	"(UTC+12:00) Fiji":                                              "Fiji Standard Time",
+	"(UTC+12:00) Petropavlovsk-Kamchatsky - Old":                    "Kamchatka Standard Time",
+	"(UTC+12:45) Chatham Islands":                                   "Chatham Islands Standard Time",
+	"(UTC+13:00) Coordinated Universal Time+13":                     "UTC+13",
+	"(UTC+13:00) Nuku'alofa":                                        "Tonga Standard Time",
+	"(UTC+13:00) Samoa":                                             "Samoa Standard Time",
+	"(UTC+14:00) Kiritimati Island":                                 "Line Islands Standard Time",
+
+	// Windows XP and older. Other "(GMT+01:00)" names match the entries above.
+	"(GMT) Greenwich Mean Time : Dublin, Edinburgh, Lisbon, London": "GMT Standard Time",
+}
@@ -0,0 +1,175 @@
+package ical
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoadLocation(t *testing.T) {
+	if _, err := time.LoadLocation("America/New_York"); err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+
+	testCases := []struct {
+		tzid, want string
+	}{
+		{"Europe/Paris", "Europe/Paris"},
+		{"Eastern Standard Time", "America/New_York"},
+		{"eastern standard time", "America/New_York"},
+		{"Eastern Daylight Time", "America/New_York"},
+		{"W. Europe Standard Time", "Europe/Berlin"},
+		{"Mexico Standard Time", "America/Mexico_City"},
+		{"(UTC+01:00) Amsterdam, Berlin, Bern, Rome, Stockholm, Vienna", "Europe/Berlin"},
+		{"(UTC+01:00) Amsterdam, Berlin...", "Europe/Berlin"},
+		{"(GMT+01.00) Amsterdam / Berlin / Bern / Rome / Stockholm / Vienna", "Europe/Berlin"},
+		{"(UTC-05:00) Eastern Time (US & Canada)", "America/New_York"},
+		{"(UTC) Dublin, Edinburgh, Lisbon, London", "Europe/London"},
+		{"(UTC+00:00) Dublin, Edinburgh, Lisbon, London", "Europe/London"},
+		{"(GMT) Greenwich Mean Time : Dublin, Edinburgh, Lisbon, London", "Europe/London"},
+		{"(UTC+05:30) Chennai, Kolkata, Mumbai, New Delhi", "Asia/Calcutta"},
+		{"/mozilla.org/20050126_1/America/New_York", "America/New_York"},
+		{"/softwarestudio.org/Olson_20011030_5/Europe/Paris", "Europe/Paris"},
+	}
+	for _, tc := range testCases {
+		loc, err := LoadLocation(tc.tzid)
+		if err != nil {
+			t.Errorf("LoadLocation(%q) = %v", tc.tzid, err)
+		} else if loc.String() != tc.want {
+			t.Errorf("LoadLocation(%q) = %v, want %v", tc.tzid, loc, tc.want)
+		}
+	}
+
+	for _, tzid := range []string{
+		"Unknown Standard Time",
+		"(UTC+01:00) Unknown",
+		"(UTC+02:00) Amsterdam, Berlin, Bern, Rome, Stockholm, Vienna",
+		"/example.org/Unknown",
+	} {
+		if loc, err := LoadLocation(tzid); err == nil {
+			t.Errorf("LoadLocation(%q) = %v, want an error", tzid, loc)
+		}
+	}
+}
+
+func TestWindowsTimezones(t *testing.T) {
+	if _, err := time.LoadLocation("America/New_York"); err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+
+	for name, iana := range windowsTimezones {
+		if _, err := time.LoadLocation(iana); err != nil {
+			t.Errorf("Windows time zone %q: %v", name, err)
+		}
+	}
+	for display, name := range windowsDisplayNames {
+		if _, ok := windowsTimezones[name]; !ok {
+			t.Errorf("Windows display name %q: unknown time zone %q", display, name)
+		}
+	}
+}
+
+func TestDefaultTimezoneResolver(t *testing.T) {
+	defer func(resolver TimezoneResolver) {
+		DefaultTimezoneResolver = resolver
+	}(DefaultTimezoneResolver)
+
+	loc := time.FixedZone("Custom", 3*60*60)
+	DefaultTimezoneResolver = TimezoneResolverFunc(func(tzid string) (*time.Location, error) {
+		if tzid == "Custom" {
+			return loc, nil
+		}
+		return LoadLocation(tzid)
+	})
+
+	event := NewEvent()
+	event.Props.Set(&Prop{
+		Name:   PropDateTimeStart,
+		Params: Params{PropTimezoneID: []string{"Custom"}},
+		Value:  "20240101T090000",
+	})
+	event.Props.Set(&Prop{
+		Name:   PropRecurrenceRule,
+		Params: make(Params),
+		Value:  "FREQ=DAILY;COUNT=2",
+	})
+
+	want := time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC)
+	if got, err := event.Props.Get(PropDateTimeStart).DateTime(nil); err != nil {
+		t.Errorf("Prop.DateTime() = %v", err)
+	} else if !got.Equal(want) {
+		t.Errorf("Prop.DateTime() = %v, want %v", got, want)
+	}
+
+	set, err := event.Component.RecurrenceSet(nil)
+	if err != nil {
+		t.Fatalf("Component.RecurrenceSet() = %v", err)
+	}
+	if got := set.All(); len(got) != 2 || !got[1].Equal(want.AddDate(0, 0, 1)) {
+		t.Errorf("Component.RecurrenceSet().All() = %v", got)
+	}
+
+	// VTIMEZONE components take precedence
+	cal := NewCalendar()
+	cal.Children = append(cal.Children, NewTimezone(time.UTC, want, want.AddDate(1, 0, 0)))
+	cal.Children[0].Props.SetText(PropTimezoneID, "Custom")
+	if got, err := cal.LoadLocation("Custom"); err != nil {
+		t.Errorf("Calendar.LoadLocation() = %v", err)
+	} else if _, offset := want.In(got).Zone(); offset != 0 {
+		t.Errorf("Calendar.LoadLocation() offset = %v, want 0", offset)
+	}
+}
+
+func TestTimezoneResolver(t *testing.T) {
+	loc := time.FixedZone("Custom", 3*60*60)
+	resolver := TimezoneResolverFunc(func(tzid string) (*time.Location, error) {
+		if tzid == "Custom" {
+			return loc, nil
+		}
+		return LoadLocation(tzid)
+	})
+
+	prop := &Prop{
+		Name:   PropDateTimeStart,
+		Params: Params{PropTimezoneID: []string{"Custom"}},
+		Value:  "20240101T090000",
+	}
+	want := time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC)
+	if got, err := prop.DateTimeWith(nil, resolver); err != nil {
+		t.Errorf("Prop.DateTimeWith() = %v", err)
+	} else if !got.Equal(want) {
+		t.Errorf("Prop.DateTimeWith() = %v, want %v", got, want)
+	}
+	if _, err := prop.DateTime(nil); err == nil {
+		t.Errorf("Prop.DateTime() = nil, want an error for an unknown TZID")
+	}
+
+	// VTIMEZONE components of the calendar take precedence over the fallback
+	cal := NewCalendar()
+	tz := NewTimezone(time.UTC, want, want.AddDate(1, 0, 0))
+	tz.Props.SetText(PropTimezoneID, "Other")
+	cal.Children = append(cal.Children, tz)
+	calResolver := cal.Resolver(resolver)
+	if got, err := calResolver.LoadLocation("Custom"); err != nil {
+		t.Errorf("Calendar.Resolver().LoadLocation() = %v", err)
+	} else if got != loc {
+		t.Errorf("Calendar.Resolver().LoadLocation() = %v, want %v", got, loc)
+	}
+	if got, err := calResolver.LoadLocation("Other"); err != nil {
+		t.Errorf("Calendar.Resolver().LoadLocation() = %v", err)
+	} else if _, offset := want.In(got).Zone(); offset != 0 {
+		t.Errorf("Calendar.Resolver().LoadLocation() offset = %v, want 0", offset)
+	}
+
+	event := NewEvent()
+	event.Props.Set(prop)
+	cal.Children = append(cal.Children, event.Component)
+	if err := cal.ConvertTimezones(time.UTC, nil); err == nil {
+		t.Errorf("Calendar.ConvertTimezones() = nil, want an error for an unknown TZID")
+	}
+	if err := cal.ConvertTimezones(time.UTC, &ConvertOptions{Resolver: resolver}); err != nil {
+		t.Fatalf("Calendar.ConvertTimezones() = %v", err)
+	}
+	if got := event.Props.Get(PropDateTimeStart).Value; got != "20240101T060000Z" {
+		t.Errorf("DTSTART = %v, want 20240101T060000Z", got)
+	}
+}